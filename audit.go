@@ -0,0 +1,209 @@
+package group
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+var ErrStoreConflict = errors.New("store conflict")
+
+// OverwritePolicy is the policy of an AuditStore on overwrites by different nodes
+type OverwritePolicy uint8
+
+const (
+	OverwriteAllow OverwritePolicy = iota // overwrites are recorded only
+	OverwriteWarn                         // overwrites are logged as warnings
+	OverwriteError                        // overwrites are rejected and fail the writing node with ErrStoreConflict
+)
+
+// Write is a store write of an AuditStore
+type Write struct {
+	Writer any // writer node key, nil if written outside group runs or by an anonymous node
+	At     time.Time
+}
+
+// Provenance is the write history of a store key
+type Provenance struct {
+	Key     any
+	Writes  []Write // in write order, the last write holds the current value
+	Readers []any   // keys of nodes that read the key
+}
+
+// AuditStore records which node wrote each store entry and when
+/*
+ * writes outside group runs never conflict, neither do overwrites by the same node
+ * pass it to GraphOptions.Audit to render data edges (writer -> reader) onto the graph
+ */
+type AuditStore struct {
+	Storer
+	policy OverwritePolicy
+
+	mu      sync.Mutex
+	entries map[any]*auditEntry
+	keys    []any // keys by first write
+}
+
+type auditEntry struct {
+	writes []auditWrite
+	reads  []auditRead
+}
+
+type auditWrite struct {
+	n  *node
+	at time.Time
+}
+
+// auditRead is a read of the value written by writer
+type auditRead struct {
+	n, writer *node
+}
+
+func NewAuditStore(store Storer, policy OverwritePolicy) *AuditStore {
+	return &AuditStore{Storer: store, policy: policy, entries: make(map[any]*auditEntry)}
+}
+
+// Store records an external write
+func (s *AuditStore) Store(key, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.record(nil, key)
+	s.Storer.Store(key, value)
+}
+
+// write records a write of key by n
+func (s *AuditStore) write(n *node, key any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e := s.entries[key]; e != nil {
+		if prev := e.writes[len(e.writes)-1].n; prev != nil && prev != n {
+			switch s.policy {
+			case OverwriteWarn:
+				slog.Warn(fmt.Sprintf("[Group::AuditStore] node %s overwrites %v written by node %s", nodeName(n), key, nodeName(prev)))
+			case OverwriteError:
+				return fmt.Errorf("%w: node %q overwrites %q written by node %q", ErrStoreConflict, nodeName(n), fmt.Sprint(key), nodeName(prev))
+			}
+		}
+	}
+	s.record(n, key)
+	return nil
+}
+
+// record appends a write of key by n, s.mu must be held
+func (s *AuditStore) record(n *node, key any) {
+	e := s.entries[key]
+	if e == nil {
+		e = &auditEntry{}
+		s.entries[key] = e
+		s.keys = append(s.keys, key)
+	}
+	e.writes = append(e.writes, auditWrite{n: n, at: time.Now()})
+}
+
+// read records a read of key by n
+func (s *AuditStore) read(n *node, key any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.entries[key]
+	if e == nil {
+		return
+	}
+	r := auditRead{n: n, writer: e.writes[len(e.writes)-1].n}
+	if !slices.Contains(e.reads, r) {
+		e.reads = append(e.reads, r)
+	}
+}
+
+// Provenance returns the write history of all keys by first write
+func (s *AuditStore) Provenance() []Provenance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	table := make([]Provenance, 0, len(s.keys))
+	for _, key := range s.keys {
+		e := s.entries[key]
+		p := Provenance{Key: key, Writes: make([]Write, len(e.writes))}
+		for i, w := range e.writes {
+			p.Writes[i] = Write{Writer: writerKey(w.n), At: w.at}
+		}
+		for _, r := range e.reads {
+			if !slices.Contains(p.Readers, r.n.key) {
+				p.Readers = append(p.Readers, r.n.key)
+			}
+		}
+		table = append(table, p)
+	}
+	return table
+}
+
+func writerKey(n *node) any {
+	if n == nil {
+		return nil
+	}
+	return n.key
+}
+
+// dataEdge is a store key passed from a writer node to a reader node
+type dataEdge struct {
+	from, to *node
+	key      any
+}
+
+// dataEdges returns the data edges between different nodes of g
+func (s *AuditStore) dataEdges(g *Group) []dataEdge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var edges []dataEdge
+	for _, key := range s.keys {
+		for _, r := range s.entries[key].reads {
+			if r.writer != nil && r.writer != r.n && r.writer.Group == g && r.n.Group == g {
+				edges = append(edges, dataEdge{from: r.writer, to: r.n, key: key})
+			}
+		}
+	}
+	return edges
+}
+
+// view returns the store view of node n over store
+func (s *AuditStore) view(n *node, store Storer) *auditView {
+	return &auditView{Storer: store, audit: s, n: n}
+}
+
+// auditView attributes store writes to a node
+type auditView struct {
+	Storer
+	audit *AuditStore
+	n     *node
+
+	mu  sync.Mutex
+	err error // first rejected write (OverwriteError)
+}
+
+func (v *auditView) Load(key any) (any, bool) {
+	value, ok := v.Storer.Load(key)
+	if ok {
+		v.audit.read(v.n, key)
+	}
+	return value, ok
+}
+
+func (v *auditView) Store(key, value any) {
+	if err := v.audit.write(v.n, key); err != nil {
+		v.mu.Lock()
+		if v.err == nil {
+			v.err = err
+		}
+		v.mu.Unlock()
+		return
+	}
+	v.Storer.Store(key, value) // not under audit lock, the node store may notify awaiting nodes
+}
+
+// Err returns the first rejected write
+func (v *auditView) Err() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.err
+}
@@ -0,0 +1,237 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrAwaitDeadlock = errors.New("await deadlock")
+
+type awaitKey struct{}
+
+type nodeState uint8
+
+const (
+	statePending nodeState = iota
+	stateRunning
+	stateDone
+	stateFailed
+)
+
+// awaitRun tracks node states, stores and awaits of a group run
+type awaitRun struct {
+	g       *Group
+	mu      sync.Mutex
+	state   []nodeState
+	waits   map[int]any   // awaited key by node idx
+	changed chan struct{} // closed and renewed on every store (while awaited) / node finish
+	waiting atomic.Int32  // awaiting nodes, stores skip the broadcast if none
+}
+
+func newAwaitRun(g *Group) *awaitRun {
+	return &awaitRun{g: g, state: make([]nodeState, len(g.nodes)), waits: make(map[int]any), changed: make(chan struct{})}
+}
+
+// broadcast wakes up awaiting nodes, r.mu must be held
+func (r *awaitRun) broadcast() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+func (r *awaitRun) start(n *node) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state[n.idx] = stateRunning
+}
+
+func (r *awaitRun) finish(n *node, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state[n.idx] = stateDone; err != nil {
+		r.state[n.idx] = stateFailed
+	}
+	r.broadcast()
+}
+
+// awaitStore notifies awaiting nodes on store
+type awaitStore struct {
+	Storer
+	run *awaitRun
+}
+
+func (s *awaitStore) Store(key, value any) {
+	s.Storer.Store(key, value)
+	if s.run.waiting.Load() == 0 {
+		return // awaiting nodes register before loading, they see the value
+	}
+	s.run.mu.Lock()
+	defer s.run.mu.Unlock()
+	s.run.broadcast()
+}
+
+// awaiter is the awaiting node of a run
+type awaiter struct {
+	run *awaitRun
+	n   *node
+}
+
+// Await blocks until key is stored by another node in the same run, or the context ends
+/*
+ * returns ErrAwaitDeadlock if the awaited node key can never be stored:
+ * the producer is the caller, has finished without storing it, is blocked by a failed upstream,
+ * or is (transitively) waiting on the caller through dependencies or awaits,
+ * if no node is left to store a non-node key
+ * and if all slots of the group limit are held by awaiting nodes, so that no other node can start
+ * outside group runs, Await does not block and fails if key is not stored
+ */
+func Await[T any](ctx context.Context, key any) (T, error) {
+	var zero T
+	store, _ := ctx.Value(fetchKey{}).(Storer)
+	if store == nil {
+		return zero, errors.New("Await called with non-storer context")
+	}
+	load := func() (T, bool, error) {
+		val, ok := store.Load(key)
+		if !ok {
+			return zero, false, nil
+		}
+		v, ok := val.(T)
+		if !ok {
+			return zero, false, fmt.Errorf("await %q: stored value of type %T is not %T", fmt.Sprint(key), val, zero)
+		}
+		return v, true, nil
+	}
+
+	a, _ := ctx.Value(awaitKey{}).(*awaiter)
+	if a == nil {
+		v, ok, err := load()
+		if err == nil && !ok {
+			err = fmt.Errorf("await %q outside group run: not stored", fmt.Sprint(key))
+		}
+		return v, err
+	}
+	r := a.run
+	r.waiting.Add(1)
+	defer func() {
+		r.mu.Lock()
+		delete(r.waits, a.n.idx)
+		r.mu.Unlock()
+		r.waiting.Add(-1)
+	}()
+	for {
+		r.mu.Lock()
+		v, ok, err := load()
+		if ok || err != nil {
+			r.mu.Unlock()
+			return v, err
+		}
+		if err := r.deadlock(a.n, key); err != nil {
+			r.mu.Unlock()
+			return zero, err
+		}
+		r.waits[a.n.idx] = key
+		changed := r.changed
+		r.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// deadlock reports whether key awaited by caller can never be stored, r.mu must be held
+func (r *awaitRun) deadlock(caller *node, key any) error {
+	if r.starved(caller) {
+		return fmt.Errorf("%w: node %q awaits %q with all %d slots held by awaiting nodes", ErrAwaitDeadlock, nodeName(caller), fmt.Sprint(key), r.g.limit)
+	}
+	idx, ok := r.g.idxMap[key]
+	if !ok {
+		// not a node key, may be Put by any node left
+		for i, st := range r.state {
+			if i != caller.idx && (st == stateRunning || st == statePending && !r.blocked(i, make([]bool, len(r.g.nodes)))) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: node %q awaits %q with no node left to store it", ErrAwaitDeadlock, nodeName(caller), fmt.Sprint(key))
+	}
+	producer := r.g.nodes[idx]
+	switch {
+	case idx == caller.idx:
+		return fmt.Errorf("%w: node %q awaits its own key", ErrAwaitDeadlock, nodeName(caller))
+	case r.state[idx] == stateDone || r.state[idx] == stateFailed:
+		return fmt.Errorf("%w: node %q awaits %q finished without storing", ErrAwaitDeadlock, nodeName(caller), nodeName(producer))
+	case r.blocked(idx, make([]bool, len(r.g.nodes))):
+		return fmt.Errorf("%w: node %q awaits %q blocked by failed upstream", ErrAwaitDeadlock, nodeName(caller), nodeName(producer))
+	case r.waitsOn(idx, caller.idx, make([]bool, len(r.g.nodes))):
+		return fmt.Errorf("%w: node %q awaits %q waiting on %q", ErrAwaitDeadlock, nodeName(caller), nodeName(producer), nodeName(caller))
+	}
+	return nil
+}
+
+// starved reports whether all slots of the group limit are held by awaiting nodes (the caller included)
+/*
+ * running nodes hold a slot each, no node can start or store until one of them returns
+ */
+func (r *awaitRun) starved(caller *node) bool {
+	if r.g.limit <= 0 {
+		return false // one slot per node
+	}
+	var running int
+	for i, st := range r.state {
+		if st != stateRunning {
+			continue
+		}
+		if _, ok := r.waits[i]; !ok && i != caller.idx {
+			return false
+		}
+		running++
+	}
+	return running >= r.g.limit
+}
+
+// blocked reports whether pending node idx never runs since a strong upstream failed
+func (r *awaitRun) blocked(idx int, visited []bool) bool {
+	if r.state[idx] != statePending || visited[idx] {
+		return false
+	}
+	visited[idx] = true
+	for _, depIdx := range r.g.nodes[idx].deps {
+		if slices.Contains(r.g.nodes[depIdx].weakTo, idx) {
+			continue
+		}
+		if r.state[depIdx] == stateFailed || r.blocked(depIdx, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// waitsOn reports whether node idx (transitively) waits on node target through unfinished dependencies or awaits
+func (r *awaitRun) waitsOn(idx, target int, visited []bool) bool {
+	if idx == target {
+		return true
+	}
+	if visited[idx] || r.state[idx] == stateDone || r.state[idx] == stateFailed {
+		return false
+	}
+	visited[idx] = true
+	if r.state[idx] == statePending {
+		for _, depIdx := range r.g.nodes[idx].deps {
+			if r.waitsOn(depIdx, target, visited) {
+				return true
+			}
+		}
+	}
+	if key, ok := r.waits[idx]; ok {
+		if producer, ok := r.g.idxMap[key]; ok && r.waitsOn(producer, target, visited) {
+			return true
+		}
+	}
+	return false
+}
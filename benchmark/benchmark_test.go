@@ -203,3 +203,78 @@ func loopGroup(b *testing.B, c *benchmarkCtx) {
 			Go(context.Background())
 	}
 }
+
+// region STORE
+//= BENCHMARK - Store
+
+var storeCases = []struct {
+	name   string
+	writes int // writes per 10 operations
+}{
+	{"ReadHeavy", 1},
+	{"Mixed", 5},
+	{"WriteHeavy", 9},
+}
+
+var stores = []struct {
+	name string
+	new  func() Storer
+}{
+	{"MapStore", func() Storer { return NewMapStore() }},
+	{"ShardedStore", func() Storer { return NewShardedStore(0) }},
+	{"SyncMapStore", func() Storer { return NewSyncMapStore() }},
+}
+
+func BenchmarkStore(b *testing.B) {
+	fmt.Println()
+	for _, c := range storeCases {
+		b.Run(c.name, func(b *testing.B) {
+			for _, s := range stores {
+				b.Run(s.name, func(b *testing.B) {
+					loopStore(b, s.new(), 256, c.writes)
+				})
+			}
+		})
+		fmt.Println()
+	}
+}
+
+func loopStore(b *testing.B, store Storer, keys, writes int) {
+	for i := range keys {
+		store.Store(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+		for pb.Next() {
+			if key := i % keys; i%10 < writes {
+				store.Store(key, i)
+			} else {
+				_, _ = store.Load(key)
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkNodeStore measures node stores within a group run against the raw store
+func BenchmarkNodeStore(b *testing.B) {
+	fmt.Println()
+	b.Run("Raw", func(b *testing.B) {
+		store := NewShardedStore(0)
+		for i := 0; b.Loop(); i++ {
+			store.Store(i%256, i)
+		}
+	})
+
+	b.Run("Group", func(b *testing.B) {
+		ctx := WithStore(context.Background(), NewShardedStore(0))
+		_ = NewGroup().AddTask(func(ctx context.Context) error {
+			for i := 0; b.Loop(); i++ {
+				Put(ctx, i%256, i)
+			}
+			return nil
+		}).Go(ctx)
+	})
+	fmt.Println()
+}
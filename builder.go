@@ -0,0 +1,32 @@
+package group
+
+import (
+	"errors"
+	"slices"
+)
+
+var ErrInvalidGroup = errors.New("invalid group")
+
+// NewBuilder returns a group in builder mode
+/*
+ * configuration mistakes (duplicate keys / dependencies, invalid retry, timeout or limit)
+ * are accumulated instead of panicking, and returned joined by Err, Build or Go
+ * use it for groups assembled from runtime config, keep NewGroup for static code
+ */
+func NewBuilder(opts ...option) *Group {
+	return newGroup(true, opts...)
+}
+
+// Err returns accumulated configuration errors and unresolved dependencies joined
+func (g *Group) Err() error {
+	return errors.Join(append(slices.Clone(g.errs), g.resolve())...)
+}
+
+// Build returns the group with accumulated configuration errors, unresolved dependencies and cycles joined
+func (g *Group) Build() (*Group, error) {
+	err := g.Err()
+	if _, cycleErr := g.levels(); cycleErr != nil && err == nil {
+		err = cycleErr
+	}
+	return g, err
+}
@@ -0,0 +1,140 @@
+package group
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Cache is a cross-run cache backend of node results
+/*
+ * values are the ones stored by nodes (nil for nodes storing nothing), ttl <= 0 means no expiration
+ * implementations must be safe for concurrent use
+ */
+type Cache interface {
+	Get(key string) (value any, ok bool)
+	Set(key string, value any, ttl time.Duration)
+}
+
+// CacheKeyFunc derives the cache key of a node execution, an empty key bypasses the cache
+type CacheKeyFunc func(ctx context.Context, shared any) string
+
+type nodeCache struct {
+	cache   Cache
+	keyFunc CacheKeyFunc
+	ttl     time.Duration
+}
+
+// WithCache memoizes successful node executions across runs
+/*
+ * a hit short-circuits the execution (including retries) and restores the stored value into the storer-context
+ * as the node would have via Store, errors are never cached
+ */
+func (n *node) WithCache(cache Cache, keyFunc CacheKeyFunc, ttl time.Duration) *node {
+	if cache == nil || keyFunc == nil {
+		n.fail("cache and cache key func must not be nil")
+		return n
+	}
+	n.cache = &nodeCache{cache: cache, keyFunc: keyFunc, ttl: ttl}
+	return n
+}
+
+// exec runs f through the cache
+func (c *nodeCache) exec(ctx context.Context, n *node, store Storer, obs observers, f func(context.Context, any) error, shared any) error {
+	key := c.keyFunc(ctx, shared)
+	if key == "" {
+		return f(ctx, shared)
+	}
+	if v, ok := c.cache.Get(key); ok {
+		obs.notify(evCacheHit, n, 0, nil)
+		if n.log {
+			slog.InfoContext(ctx, fmt.Sprintf("[Group::node -> exec] group %s: node %s cache hit", n.prefix, n.key), slog.String("cache_key", key))
+		}
+		if slot := resultSlotOf(ctx); slot != nil {
+			slot.set(v)
+		}
+		if v != nil && store != nil && n.key != nil {
+			store.Store(n.key, v)
+		}
+		return nil
+	}
+	obs.notify(evCacheMiss, n, 0, nil)
+	if n.log {
+		slog.InfoContext(ctx, fmt.Sprintf("[Group::node -> exec] group %s: node %s cache miss", n.prefix, n.key), slog.String("cache_key", key))
+	}
+	if err := f(ctx, shared); err != nil {
+		return err
+	}
+	v, _ := resultSlotOf(ctx).get() // the value stored by this execution, not by other nodes or runs
+	c.cache.Set(key, v, c.ttl)
+	return nil
+}
+
+// LRUCache is an in-memory Cache evicting the least recently used entries beyond capacity
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   any
+	expires time.Time // zero for no expiration
+}
+
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		panic("capacity must be positive")
+	}
+	return &LRUCache{capacity: capacity, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (c *LRUCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *LRUCache) Set(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.value, e.expires = value, expires
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.ll.Len() > c.capacity {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.items, el.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of entries (including expired ones not evicted yet)
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
@@ -0,0 +1,55 @@
+package group
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the group (nodes, edges, node specs, options and pending dependencies)
+/*
+ * use a group as a template: define it once, then clone and modify it per instance
+ *   g := tmpl.Clone().WithOptions(WithTimeout(t))
+ *   g.Node("fetch").WithFunc(tenantFetch).WithRetry(3)
+ *   g.Remove("audit", RemoveDetach)
+ * node funcs and interceptors are shared, the error collector channel is shared, in-flight executions (Dedup) are not
+ * futures are not resolved by runs of clones, read results of clones from a storer-context (keyed by the future)
+ */
+func (g *Group) Clone() *Group {
+	_ = g.resolve() // edges are linked before copying
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c := &Group{
+		x:       g.x,
+		nodes:   make([]*node, 0, len(g.nodes)),
+		idxMap:  maps.Clone(g.idxMap),
+		pending: slices.Clone(g.pending),
+		Options: g.Options,
+	}
+	c.errs = slices.Clone(g.errs)
+	for _, n := range g.nodes {
+		c.nodes = append(c.nodes, &node{
+			idx:       n.idx,
+			key:       n.key,
+			deps:      slices.Clone(n.deps),
+			to:        slices.Clone(n.to),
+			weakTo:    slices.Clone(n.weakTo),
+			f:         n.f,
+			namespace: n.namespace,
+			nodeSpec:  n.nodeSpec,
+			Group:     c,
+		})
+		if n.dedup != nil {
+			// clones do not share in-flight executions
+			c.nodes[n.idx].Dedup(n.dedup.keyFunc)
+		}
+	}
+	return c
+}
+
+// WithOptions applies options to the group, overriding the current ones
+func (g *Group) WithOptions(opts ...option) *Group {
+	for _, o := range opts {
+		o(&g.Options)
+	}
+	return g
+}
@@ -0,0 +1,181 @@
+// Command group validates, inspects and renders declarative group definitions
+/*
+ * usage:
+ *   group validate <definition>
+ *   group inspect <definition>
+ *   group render [-format dot|svg|png|jpg|mermaid] [-o output] [-rankdir TB|LR|BT|RL] [-spec] <definition>
+ * funcs and conditions referenced by the definition are replaced by no-op stubs,
+ * so definitions can be checked without the Go code registering them
+ * the critical path is the longest dependency chain by node count, node durations are not known statically
+ */
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-graphviz"
+	"github.com/goccy/go-graphviz/cgraph"
+
+	"github.com/oatcatx/group"
+)
+
+const usage = `usage:
+  group validate <definition>
+  group inspect <definition>
+  group render [-format dot|svg|png|jpg|mermaid] [-o output] [-rankdir TB|LR|BT|RL] [-spec] <definition>
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "validate":
+		return validate(args, w)
+	case "inspect":
+		return inspect(args, w)
+	case "render":
+		return render(args, w)
+	default:
+		return fmt.Errorf("unknown command %q\n%s", cmd, usage)
+	}
+}
+
+// load parses the definition file and builds the group against a stub registry
+func load(filename string) (*group.Definition, *group.Group, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	def, err := group.ParseDefinition(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	reg := group.NewRegistry()
+	funcs, conds := make(map[string]bool), make(map[string]bool)
+	for _, nd := range def.Nodes {
+		if nd.Func != "" && !funcs[nd.Func] {
+			funcs[nd.Func] = true
+			reg.Register(nd.Func, func() error { return nil })
+		}
+		if nd.Condition != "" && !conds[nd.Condition] {
+			conds[nd.Condition] = true
+			reg.RegisterCondition(nd.Condition, func(context.Context, any) bool { return true })
+		}
+	}
+	g, err := def.Build(reg)
+	return def, g, err
+}
+
+func validate(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return errors.New(usage)
+	}
+	def, g, err := load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	for _, issue := range g.Issues() { // warnings only
+		fmt.Fprintf(w, "warning: %s\n", issue)
+	}
+	var edges int
+	for _, nd := range def.Nodes {
+		edges += len(nd.Deps) + len(nd.WeakDeps)
+	}
+	fmt.Fprintf(w, "ok: %d nodes, %d edges\n", len(def.Nodes), edges)
+	return nil
+}
+
+func inspect(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return errors.New(usage)
+	}
+	_, g, err := load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	levels, err := g.Levels()
+	if err != nil {
+		return err
+	}
+	path, err := g.CriticalPath()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "levels:")
+	for i, level := range levels {
+		fmt.Fprintf(w, "  %d: %s\n", i, join(level, ", "))
+	}
+	fmt.Fprintf(w, "critical path (%d nodes): %s\n", len(path), join(path, " -> "))
+	return nil
+}
+
+func join(keys []any, sep string) string {
+	s := make([]string, 0, len(keys))
+	for _, key := range keys {
+		s = append(s, fmt.Sprint(key))
+	}
+	return strings.Join(s, sep)
+}
+
+func render(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	format := fs.String("format", "dot", "output format: dot, svg, png, jpg or mermaid")
+	output := fs.String("o", "", "output file (default stdout)")
+	rankDir := fs.String("rankdir", "TB", "rank direction: TB, LR, BT or RL")
+	spec := fs.Bool("spec", true, "show node spec details")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return errors.New(usage)
+	}
+	_, g, err := load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	opts := group.DefaultGraphOptions()
+	opts.RankDir, opts.ShowNodeSpec = cgraph.RankDir(strings.ToUpper(*rankDir)), *spec
+	var out []byte
+	switch strings.ToLower(*format) {
+	case "mermaid":
+		out = []byte(g.Mermaid(opts))
+	case "dot":
+		dot, err := g.DOT(context.Background(), opts)
+		if err != nil {
+			return err
+		}
+		out = []byte(dot)
+	case "svg", "png", "jpg":
+		opts.Format = graphviz.Format(strings.ToLower(*format))
+		var buf strings.Builder
+		if err := g.RenderGraph(context.Background(), opts, &buf); err != nil {
+			return err
+		}
+		out = []byte(buf.String())
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	if *output == "" {
+		_, err = w.Write(out)
+		return err
+	}
+	return os.WriteFile(*output, out, 0644)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeDefinition(t *testing.T, content string) string {
+	filename := filepath.Join(t.TempDir(), "workflow.yaml")
+	assert.Nil(t, os.WriteFile(filename, []byte(content), 0644))
+	return filename
+}
+
+const diamond = `
+prefix: diamond
+nodes:
+  - {key: a, func: load}
+  - {key: b, func: transform, deps: [a]}
+  - {key: c, func: transform, deps: [a], retry: 2}
+  - {key: d, func: save, deps: [b], weak_deps: [c], fast_fail: true}
+  - {key: e, func: notify, deps: [d], condition: enabled}
+`
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	assert.Nil(t, run([]string{"validate", writeDefinition(t, diamond)}, &out))
+	assert.Equal(t, "ok: 5 nodes, 5 edges\n", out.String())
+
+	err := run([]string{"validate", writeDefinition(t, `
+nodes:
+  - {key: a, func: f, deps: [c]}
+  - {key: b, func: f, deps: [a]}
+  - {key: c, func: f, deps: [b]}
+  - {key: d, func: f, deps: [c]}
+  - {key: e, func: f}
+`)}, &out)
+	assert.ErrorContains(t, err, "dependency cycle")
+
+	err = run([]string{"validate", writeDefinition(t, `
+nodes:
+  - {key: a, func: f, deps: [x]}
+  - {key: b, func: f, weak_deps: [a]}
+`)}, &out)
+	assert.ErrorContains(t, err, `node "a": missing dependency "x"`)
+
+	out.Reset()
+	assert.Nil(t, run([]string{"validate", writeDefinition(t, `
+nodes:
+  - {key: a, func: f}
+  - {key: b, func: f, deps: [a]}
+  - {key: c, func: f, deps: [a, b]}
+`)}, &out))
+	assert.Equal(t, "warning: redundant edge: \"c\" -> \"a\" is implied by \"b\"\nok: 3 nodes, 3 edges\n", out.String())
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	assert.Nil(t, run([]string{"inspect", writeDefinition(t, diamond)}, &out))
+	assert.Equal(t, `levels:
+  0: a
+  1: b, c
+  2: d
+  3: e
+critical path (4 nodes): a -> b -> d -> e
+`, out.String())
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+	filename := writeDefinition(t, diamond)
+
+	var out strings.Builder
+	assert.Nil(t, run([]string{"render", "-format", "mermaid", "-rankdir", "lr", filename}, &out))
+	assert.Contains(t, out.String(), "flowchart LR")
+	assert.Contains(t, out.String(), "n2 -.->|weak| n3")
+
+	out.Reset()
+	assert.Nil(t, run([]string{"render", filename}, &out))
+	assert.Contains(t, out.String(), "a -> b")
+
+	output := filepath.Join(t.TempDir(), "graph.svg")
+	assert.Nil(t, run([]string{"render", "-format", "svg", "-o", output, filename}, &out))
+	svg, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	assert.Contains(t, string(svg), "<svg")
+
+	assert.ErrorContains(t, run([]string{"render", "-format", "gif", filename}, &out), "unknown format")
+	assert.ErrorContains(t, run([]string{"unknown"}, &out), "unknown command")
+}
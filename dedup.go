@@ -0,0 +1,100 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// DedupKeyFunc derives the dedup key of a node execution, an empty key disables deduplication
+type DedupKeyFunc func(ctx context.Context, shared any) string
+
+// nodeDedup is the singleflight of a node across concurrent runs
+type nodeDedup struct {
+	keyFunc DedupKeyFunc
+	mu      sync.Mutex
+	calls   map[string]*dedupCall
+}
+
+type dedupCall struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	value   any
+	stored  bool
+	err     error
+}
+
+// Dedup shares one in-flight execution among concurrent runs with the same dedup key
+/*
+ * the leading run executes the node, other runs wait for it and get its result / error,
+ * the stored value is propagated into the storer-context of each run
+ * the execution is detached from the leader's cancellation and is cancelled only when all waiting runs leave
+ */
+func (n *node) Dedup(keyFunc DedupKeyFunc) *node {
+	if keyFunc == nil {
+		n.fail("dedup key func must not be nil")
+		return n
+	}
+	n.dedup = &nodeDedup{keyFunc: keyFunc, calls: make(map[string]*dedupCall)}
+	return n
+}
+
+// exec runs f through the singleflight
+func (d *nodeDedup) exec(ctx context.Context, n *node, store Storer, f func(context.Context, any) error, shared any) error {
+	key := d.keyFunc(ctx, shared)
+	if key == "" {
+		return f(ctx, shared)
+	}
+
+	d.mu.Lock()
+	c, ok := d.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &dedupCall{done: make(chan struct{}), cancel: cancel}
+		d.calls[key] = c
+		go func() {
+			defer cancel()
+			err := n.safeRun(callCtx, func() error { return f(callCtx, shared) })
+			d.mu.Lock()
+			if slot := resultSlotOf(callCtx); err == nil && slot != nil {
+				c.value, c.stored = slot.get()
+			}
+			c.err = err
+			if d.calls[key] == c {
+				delete(d.calls, key)
+			}
+			d.mu.Unlock()
+			close(c.done)
+		}()
+	} else if n.log {
+		slog.InfoContext(ctx, fmt.Sprintf("[Group::node -> exec] group %s: node %s dedup shared", n.prefix, n.key), slog.String("dedup_key", key))
+	}
+	c.waiters++
+	d.mu.Unlock()
+
+	select {
+	case <-c.done:
+		if c.err == nil && c.stored {
+			if slot := resultSlotOf(ctx); slot != nil {
+				slot.set(c.value)
+			}
+			if store != nil {
+				store.Store(n.key, c.value)
+			}
+		}
+		return c.err
+	case <-ctx.Done():
+		d.mu.Lock()
+		if c.waiters--; c.waiters == 0 {
+			// last waiter leaves, no one wants the result
+			c.cancel()
+			if d.calls[key] == c {
+				delete(d.calls, key)
+			}
+		}
+		d.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,319 @@
+package group
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidDefinition = errors.New("invalid definition")
+	ErrUnknownFunc       = errors.New("unknown func")
+	ErrUnknownCondition  = errors.New("unknown condition")
+	ErrMissingDependency = errors.New("missing dependency")
+	ErrDuplicateKey      = errors.New("duplicate node key")
+	ErrCycle             = errors.New("dependency cycle")
+)
+
+// Definition is a declarative group definition loaded from YAML or JSON
+/*
+ * prefix: pipeline
+ * limit: 4
+ * timeout: 30s
+ * nodes:
+ *   - key: fetch
+ *     func: fetchData
+ *     retry: 2
+ *     timeout: 5s
+ *   - key: process
+ *     func: processData
+ *     deps: [fetch]
+ *     fast_fail: true
+ *     condition: enabled
+ */
+type Definition struct {
+	Prefix  string           `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Limit   int              `json:"limit,omitempty" yaml:"limit,omitempty"`
+	Timeout Duration         `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Nodes   []NodeDefinition `json:"nodes" yaml:"nodes"`
+}
+
+type NodeDefinition struct {
+	Key        string   `json:"key,omitempty" yaml:"key,omitempty"`
+	Func       string   `json:"func" yaml:"func"`
+	Deps       []string `json:"deps,omitempty" yaml:"deps,omitempty"`
+	WeakDeps   []string `json:"weak_deps,omitempty" yaml:"weak_deps,omitempty"`
+	FastFail   bool     `json:"fast_fail,omitempty" yaml:"fast_fail,omitempty"`
+	SilentFail bool     `json:"silent_fail,omitempty" yaml:"silent_fail,omitempty"`
+	Retry      int      `json:"retry,omitempty" yaml:"retry,omitempty"`
+	Timeout    Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Condition  string   `json:"condition,omitempty" yaml:"condition,omitempty"`
+}
+
+// Duration is a time.Duration encoded as a duration string (e.g. "1.5s", "300ms")
+type Duration time.Duration
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+func (d Duration) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Registry holds named funcs and conditions referenced by definitions
+/*
+ * supported funcs are the ones accepted by [Add...] methods:
+ * func() error, func(any) error, func() (any, error), func(any) (any, error),
+ * func(context.Context) error, func(context.Context, any) error,
+ * func(context.Context) (any, error), func(context.Context, any) (any, error),
+ * Node and AutoNode implementations
+ * funcs returning a value are auto nodes (CAUTION: will PANIC if not used with storer-context)
+ */
+type Registry struct {
+	funcs map[string]any
+	conds map[string]NodeConditionFunc
+}
+
+func NewRegistry() *Registry {
+	return &Registry{funcs: make(map[string]any), conds: make(map[string]NodeConditionFunc)}
+}
+
+// Register registers a func or Node / AutoNode implementation by name
+func (r *Registry) Register(name string, f any) *Registry {
+	if _, ok := nodeFunc(f); !ok {
+		panic(fmt.Sprintf("unsupported func type %T for %q", f, name))
+	}
+	if _, ok := r.funcs[name]; ok {
+		panic(fmt.Sprintf("duplicate func %q", name))
+	}
+	r.funcs[name] = f
+	return r
+}
+
+// RegisterCondition registers a node condition by name
+func (r *Registry) RegisterCondition(name string, f NodeConditionFunc) *Registry {
+	if _, ok := r.conds[name]; ok {
+		panic(fmt.Sprintf("duplicate condition %q", name))
+	}
+	r.conds[name] = f
+	return r
+}
+
+// nodeFunc adapts supported func types to the node func
+func nodeFunc(f any) (func(context.Context, any) error, bool) {
+	switch f := f.(type) {
+	case func() error:
+		return func(context.Context, any) error { return f() }, true
+	case func(any) error:
+		return func(_ context.Context, shared any) error { return f(shared) }, true
+	case func() (any, error):
+		return autoWrapper(func(context.Context, any) (any, error) { return f() }), true
+	case func(any) (any, error):
+		return autoWrapper(func(_ context.Context, shared any) (any, error) { return f(shared) }), true
+	case func(context.Context) error:
+		return func(ctx context.Context, _ any) error { return f(ctx) }, true
+	case func(context.Context, any) error:
+		return f, true
+	case func(context.Context) (any, error):
+		return autoWrapper(func(ctx context.Context, _ any) (any, error) { return f(ctx) }), true
+	case func(context.Context, any) (any, error):
+		return autoWrapper(f), true
+	case Node:
+		return f.Exec, true
+	case AutoNode:
+		return autoWrapper(f.Exec), true
+	}
+	return nil, false
+}
+
+// ParseDefinition parses a definition from JSON or YAML, unknown fields are rejected
+func ParseDefinition(data []byte) (*Definition, error) {
+	var def Definition
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&def); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidDefinition, err)
+		}
+		return &def, nil
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&def); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDefinition, err)
+	}
+	return &def, nil
+}
+
+// Load builds a group from a JSON or YAML definition against the registry
+func Load(data []byte, reg *Registry, opts ...option) (*Group, error) {
+	def, err := ParseDefinition(data)
+	if err != nil {
+		return nil, err
+	}
+	return def.Build(reg, opts...)
+}
+
+// LoadFile builds a group from a JSON or YAML definition file against the registry
+func LoadFile(filename string, reg *Registry, opts ...option) (*Group, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data, reg, opts...)
+}
+
+// Validate checks the definition against the registry and returns all problems joined
+func (d *Definition) Validate(reg *Registry) error {
+	var errs []error
+	if d.Limit < 0 {
+		errs = append(errs, fmt.Errorf("%w: limit must be non-negative", ErrInvalidDefinition))
+	}
+	if d.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("%w: timeout must be non-negative", ErrInvalidDefinition))
+	}
+	keys := make(map[string]struct{}, len(d.Nodes))
+	for i, nd := range d.Nodes {
+		key := nd.key(reg)
+		if key == "" {
+			errs = append(errs, fmt.Errorf("%w: node #%d: missing key", ErrInvalidDefinition, i))
+			continue
+		}
+		if _, ok := keys[key]; ok {
+			errs = append(errs, fmt.Errorf("%w %q", ErrDuplicateKey, key))
+		}
+		keys[key] = struct{}{}
+	}
+	for _, nd := range d.Nodes {
+		key := nd.key(reg)
+		if key == "" {
+			continue
+		}
+		if _, ok := reg.funcs[nd.Func]; !ok {
+			errs = append(errs, fmt.Errorf("node %q: %w %q", key, ErrUnknownFunc, nd.Func))
+		}
+		if _, ok := reg.conds[nd.Condition]; nd.Condition != "" && !ok {
+			errs = append(errs, fmt.Errorf("node %q: %w %q", key, ErrUnknownCondition, nd.Condition))
+		}
+		if nd.Retry < 0 {
+			errs = append(errs, fmt.Errorf("%w: node %q: retry must be non-negative", ErrInvalidDefinition, key))
+		}
+		if nd.Timeout < 0 {
+			errs = append(errs, fmt.Errorf("%w: node %q: timeout must be non-negative", ErrInvalidDefinition, key))
+		}
+		deps, weakDeps := nd.deps(reg)
+		seen := make(map[string]struct{}, len(deps)+len(weakDeps))
+		for _, dep := range append(deps, weakDeps...) {
+			if _, ok := keys[dep]; !ok {
+				errs = append(errs, fmt.Errorf("node %q: %w %q", key, ErrMissingDependency, dep))
+			}
+			if dep == key {
+				errs = append(errs, fmt.Errorf("%w: node %q depends on itself", ErrCycle, key))
+			}
+			if _, ok := seen[dep]; ok {
+				errs = append(errs, fmt.Errorf("%w: node %q: duplicate dependency %q", ErrInvalidDefinition, key, dep))
+			}
+			seen[dep] = struct{}{}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Build validates the definition and builds the group, opts are applied after definition options
+func (d *Definition) Build(reg *Registry, opts ...option) (*Group, error) {
+	if err := d.Validate(reg); err != nil {
+		return nil, err
+	}
+	var defOpts []option
+	if d.Prefix != "" {
+		defOpts = append(defOpts, WithPrefix(d.Prefix))
+	}
+	if d.Limit > 0 {
+		defOpts = append(defOpts, WithLimit(d.Limit))
+	}
+	if d.Timeout > 0 {
+		defOpts = append(defOpts, WithTimeout(time.Duration(d.Timeout)))
+	}
+	g := NewGroup(append(defOpts, opts...)...)
+	for _, nd := range d.Nodes {
+		f, _ := nodeFunc(reg.funcs[nd.Func])
+		n := g.addNode(f).Key(nd.key(reg))
+		if nd.FastFail {
+			n.FastFail()
+		}
+		if nd.SilentFail {
+			n.SilentFail()
+		}
+		if nd.Retry > 0 {
+			n.WithRetry(nd.Retry)
+		}
+		if nd.Timeout > 0 {
+			n.WithTimeout(time.Duration(nd.Timeout))
+		}
+		if nd.Condition != "" {
+			n.WithCondition(reg.conds[nd.Condition])
+		}
+	}
+	// dependencies are added after all nodes so that definitions can be in any order
+	for _, nd := range d.Nodes {
+		n, deps, weakDeps := g.Node(nd.key(reg)), []any{}, []any{}
+		ds, wds := nd.deps(reg)
+		for _, dep := range ds {
+			deps = append(deps, dep)
+		}
+		for _, dep := range wds {
+			weakDeps = append(weakDeps, dep)
+		}
+		n.Dep(deps...).WeakDep(weakDeps...)
+	}
+	if msg := g.Verify(false); msg != "" {
+		return nil, fmt.Errorf("%w: %s", ErrCycle, strings.TrimPrefix(msg, "dependency cycle detected: "))
+	}
+	return g, nil
+}
+
+// key of the node definition, defaults to the key of registered Node / AutoNode
+func (nd NodeDefinition) key(reg *Registry) string {
+	if nd.Key != "" {
+		return nd.Key
+	}
+	if n, ok := reg.funcs[nd.Func].(baseNode); ok {
+		if key, ok := n.Key().(string); ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// deps of the node definition, defaults to the deps of registered Node / AutoNode
+func (nd NodeDefinition) deps(reg *Registry) (deps, weakDeps []string) {
+	if len(nd.Deps) > 0 || len(nd.WeakDeps) > 0 {
+		return nd.Deps, nd.WeakDeps
+	}
+	if n, ok := reg.funcs[nd.Func].(baseNode); ok {
+		for _, dep := range n.Dep() {
+			if key, ok := dep.(string); ok {
+				deps = append(deps, key)
+			}
+		}
+		for _, dep := range n.WeakDep() {
+			if key, ok := dep.(string); ok {
+				weakDeps = append(weakDeps, key)
+			}
+		}
+	}
+	return
+}
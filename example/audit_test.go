@@ -0,0 +1,120 @@
+package group
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region AUDIT
+func TestAuditStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("provenance", func(t *testing.T) {
+		t.Parallel()
+		audit := NewAuditStore(NewMapStore(), OverwriteAllow)
+		ctx := WithStore(context.Background(), audit)
+		Put(ctx, "config", "v1") // written outside the run
+
+		before := time.Now()
+		err := NewGroup().
+			AddTask(func(ctx context.Context) error {
+				cfg, _ := Fetch[string](ctx, "config")
+				Store(ctx, cfg+"-a")
+				return nil
+			}).Key("a").
+			AddTask(func(ctx context.Context) error {
+				a, _ := Fetch[string](ctx, "a")
+				Put(ctx, "config", a)
+				return nil
+			}).Key("b").Dep("a").Group.Go(ctx)
+		assert.NoError(t, err)
+
+		table := audit.Provenance()
+		assert.Len(t, table, 2)
+		assert.Equal(t, "config", table[0].Key)
+		assert.Len(t, table[0].Writes, 2)
+		assert.Nil(t, table[0].Writes[0].Writer)
+		assert.Equal(t, "b", table[0].Writes[1].Writer)
+		assert.False(t, table[0].Writes[1].At.Before(before))
+		assert.Equal(t, []any{"a"}, table[0].Readers)
+		assert.Equal(t, "a", table[1].Key)
+		assert.Equal(t, "a", table[1].Writes[0].Writer)
+		assert.Equal(t, []any{"b"}, table[1].Readers)
+
+		v, _ := Fetch[string](ctx, "config")
+		assert.Equal(t, "v1-a", v)
+	})
+
+	t.Run("allow", func(t *testing.T) {
+		t.Parallel()
+		audit := NewAuditStore(NewMapStore(), OverwriteAllow)
+		ctx := WithStore(context.Background(), audit)
+		assert.NoError(t, conflictGroup(NewGroup()).Go(ctx))
+		v, _ := Fetch[int](ctx, "shared")
+		assert.Equal(t, 2, v) // later write wins
+		assert.Len(t, audit.Provenance()[0].Writes, 2)
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		prev := slog.Default()
+		t.Cleanup(func() { slog.SetDefault(prev) })
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		audit := NewAuditStore(NewMapStore(), OverwriteWarn)
+		assert.NoError(t, conflictGroup(NewGroup(WithLogger(logger))).Go(WithStore(context.Background(), audit)))
+		assert.Contains(t, buf.String(), "node b overwrites shared written by node a")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+		audit := NewAuditStore(NewMapStore(), OverwriteError)
+		ctx := WithStore(context.Background(), audit)
+		err := conflictGroup(NewGroup()).Go(ctx)
+		assert.ErrorIs(t, err, ErrStoreConflict)
+		assert.ErrorContains(t, err, `node "b" overwrites "shared" written by node "a"`)
+		v, _ := Fetch[int](ctx, "shared")
+		assert.Equal(t, 1, v) // rejected write
+		assert.Len(t, audit.Provenance()[0].Writes, 1)
+	})
+
+	t.Run("same node", func(t *testing.T) {
+		t.Parallel()
+		audit := NewAuditStore(NewMapStore(), OverwriteError)
+		g := NewGroup().AddTask(func(ctx context.Context) error {
+			Put(ctx, "shared", 1)
+			Put(ctx, "shared", 2)
+			return nil
+		}).Key("a").Group
+		ctx := WithStore(context.Background(), audit)
+		assert.NoError(t, g.Go(ctx))
+		assert.NoError(t, g.Go(ctx)) // reruns are not conflicts
+		assert.Len(t, audit.Provenance()[0].Writes, 4)
+	})
+
+	t.Run("strict store", func(t *testing.T) {
+		t.Parallel()
+		audit := NewAuditStore(NewMapStore(), OverwriteError)
+		err := NewGroup(WithStrictStore(StrictError)).
+			AddTask(func(ctx context.Context) error { Store(ctx, 1); return nil }).Key("a").
+			AddTask(func(ctx context.Context) error {
+				_, ok := Fetch[int](ctx, "a")
+				assert.True(t, ok)
+				return nil
+			}).Key("b").Dep("a").Group.Go(WithStore(context.Background(), audit))
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"b"}, audit.Provenance()[0].Readers)
+	})
+}
+
+func conflictGroup(g *Group) *Group {
+	return g.
+		AddTask(func(ctx context.Context) error { Put(ctx, "shared", 1); return nil }).Key("a").
+		AddTask(func(ctx context.Context) error { Put(ctx, "shared", 2); return nil }).Key("b").Dep("a").Group
+}
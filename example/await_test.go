@@ -0,0 +1,157 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region AWAIT
+
+func TestAwait(t *testing.T) {
+	t.Parallel()
+
+	var storeCtx = func() context.Context { return WithStore(context.Background(), NewMapStore()) }
+
+	t.Run("sibling value", func(t *testing.T) {
+		t.Parallel()
+		var got int
+		var gotPut string
+		err := NewGroup().
+			AddAutoTask(func(ctx context.Context) (any, error) {
+				time.Sleep(100 * time.Millisecond)
+				Put(ctx, "token", "t")
+				return 42, nil
+			}).Key("slow").
+			AddTask(func(ctx context.Context) (err error) {
+				if gotPut, err = Await[string](ctx, "token"); err != nil {
+					return err
+				}
+				got, err = Await[int](ctx, "slow")
+				return err
+			}).Key("mid").
+			Go(storeCtx())
+		assert.NoError(t, err)
+		assert.Equal(t, 42, got)
+		assert.Equal(t, "t", gotPut)
+	})
+
+	t.Run("free slot", func(t *testing.T) {
+		t.Parallel()
+		var got int
+		err := NewGroup(WithLimit(2)).
+			AddTask(func(ctx context.Context) (err error) { got, err = Await[int](ctx, "b"); return }).Key("a").
+			AddAutoTask(func(ctx context.Context) (any, error) {
+				time.Sleep(20 * time.Millisecond)
+				return 1, nil
+			}).Key("b").
+			Go(storeCtx())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		t.Parallel()
+		err := NewGroup().
+			AddAutoRunner(func() (any, error) { return 1, nil }).Key("a").
+			AddTask(func(ctx context.Context) error { _, err := Await[string](ctx, "a"); return err }).Key("b").
+			Go(storeCtx())
+		assert.ErrorContains(t, err, `await "a": stored value of type int is not string`)
+	})
+
+	var deadlocks = []struct {
+		name string
+		g    func() *Group
+		msg  string
+	}{
+		{"own key", func() *Group {
+			return NewGroup().AddTask(func(ctx context.Context) error { _, err := Await[int](ctx, "a"); return err }).Key("a").Group
+		}, `node "a" awaits its own key`},
+		{"finished without storing", func() *Group {
+			return NewGroup().
+				AddRunner(func() error { return nil }).Key("a").
+				AddTask(func(ctx context.Context) error {
+					time.Sleep(50 * time.Millisecond)
+					_, err := Await[int](ctx, "a")
+					return err
+				}).Key("b").Group
+		}, `node "b" awaits "a" finished without storing`},
+		{"downstream producer", func() *Group {
+			return NewGroup().
+				AddTask(func(ctx context.Context) error { _, err := Await[int](ctx, "b"); return err }).Key("a").
+				AddAutoRunner(func() (any, error) { return 1, nil }).Key("b").Dep("a").Group
+		}, `node "a" awaits "b" waiting on "a"`},
+		{"mutual awaits", func() *Group {
+			return NewGroup().
+				AddTask(func(ctx context.Context) error { _, err := Await[int](ctx, "b"); return err }).Key("a").
+				AddTask(func(ctx context.Context) error {
+					time.Sleep(50 * time.Millisecond)
+					_, err := Await[int](ctx, "a")
+					return err
+				}).Key("b").Group
+		}, `node "b" awaits "a" waiting on "b"`},
+		{"blocked producer", func() *Group {
+			return NewGroup().
+				AddRunner(func() error { return errors.New("failed") }).Key("f").
+				AddAutoRunner(func() (any, error) { return 1, nil }).Key("p").Dep("f").
+				AddTask(func(ctx context.Context) error {
+					time.Sleep(50 * time.Millisecond)
+					_, err := Await[int](ctx, "p")
+					return err
+				}).Key("a").Group
+		}, `node "a" awaits "p" blocked by failed upstream`},
+		{"no node left", func() *Group {
+			return NewGroup().
+				AddRunner(func() error { return nil }).Key("a").
+				AddTask(func(ctx context.Context) error {
+					time.Sleep(50 * time.Millisecond)
+					_, err := Await[int](ctx, "never")
+					return err
+				}).Key("b").Group
+		}, `node "b" awaits "never" with no node left to store it`},
+		{"limit starvation", func() *Group {
+			return NewGroup(WithLimit(1)).
+				AddTask(func(ctx context.Context) error { _, err := Await[int](ctx, "b"); return err }).Key("a").
+				AddAutoRunner(func() (any, error) { return 1, nil }).Key("b").Group
+		}, `node "a" awaits "b" with all 1 slots held by awaiting nodes`},
+	}
+	for _, c := range deadlocks {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithTimeout(storeCtx(), time.Second)
+			defer cancel()
+			err := c.g().Go(ctx)
+			assert.ErrorIs(t, err, ErrAwaitDeadlock)
+			assert.ErrorContains(t, err, c.msg)
+		})
+	}
+
+	t.Run("context end", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(storeCtx(), 50*time.Millisecond)
+		defer cancel()
+		err := NewGroup().
+			AddTask(func(ctx context.Context) error { <-ctx.Done(); return nil }).Key("a").
+			AddTask(func(ctx context.Context) error { _, err := Await[int](ctx, "a"); return err }).Key("b").
+			Go(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("outside run", func(t *testing.T) {
+		t.Parallel()
+		_, err := Await[int](context.Background(), "a")
+		assert.EqualError(t, err, "Await called with non-storer context")
+		ctx := storeCtx()
+		Put(ctx, "a", 1)
+		v, err := Await[int](ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v)
+		_, err = Await[int](ctx, "b")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,69 @@
+package group
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region BUILDER
+
+func TestGroupBuilder(t *testing.T) {
+	t.Parallel()
+
+	var f = func() error { return nil }
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		g, err := NewBuilder(WithLimit(2)).
+			AddRunner(f).Key("b").Dep("a").
+			AddRunner(f).Key("a").Group.
+			Build()
+		assert.NoError(t, err)
+		assert.NoError(t, g.Go(context.Background()))
+	})
+
+	t.Run("accumulated", func(t *testing.T) {
+		t.Parallel()
+		g := NewBuilder(WithLimit(0), WithTimeout(-1)).
+			AddRunner(f).Key("a").WithRetry(-1).WithTimeout(0).
+			AddRunner(f).Key("a").
+			AddRunner(f).Key("b").Dep("x").WeakDep("a", "a").Group
+		err := g.Err()
+		assert.ErrorIs(t, err, ErrInvalidGroup)
+		assert.ErrorIs(t, err, ErrMissingDependency)
+		for _, msg := range []string{
+			"limit must be positive",
+			"timeout must be positive",
+			"retry times must be non-negative",
+			`duplicate node key "a"`,
+			`duplicate dependency "b" -> "a"`,
+			`missing dependency "b" -> "x"`,
+		} {
+			assert.ErrorContains(t, err, msg)
+		}
+		_, buildErr := g.Build()
+		assert.Equal(t, err.Error(), buildErr.Error())
+		assert.Equal(t, err.Error(), g.Go(context.Background()).Error())
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewBuilder().
+			AddRunner(f).Key("a").Dep("b").
+			AddRunner(f).Key("b").Dep("a").Group.
+			Build()
+		assert.ErrorIs(t, err, ErrCycle)
+	})
+
+	t.Run("static panics", func(t *testing.T) {
+		t.Parallel()
+		assert.PanicsWithValue(t, "limit must be positive", func() { NewGroup(WithLimit(0)) })
+		assert.PanicsWithValue(t, `duplicate node key "a"`, func() {
+			NewGroup().AddRunner(f).Key("a").AddRunner(f).Key("a")
+		})
+	})
+}
@@ -0,0 +1,141 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region CACHE
+
+func TestGroupCache(t *testing.T) {
+	t.Parallel()
+
+	var tenantKey = func(_ context.Context, shared any) string { return shared.(string) }
+
+	t.Run("memoize", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		cache := NewLRUCache(8)
+		g := NewGroup().
+			AddAutoSharedRunner(func(shared any) (any, error) {
+				calls.Add(1)
+				return "config of " + shared.(string), nil
+			}).Key("config").WithCache(cache, tenantKey, time.Minute).
+			AddTask(func(ctx context.Context) error {
+				if cfg, _ := Fetch[string](ctx, "config"); cfg == "" {
+					return errors.New("missing config")
+				}
+				return nil
+			}).Key("use").Dep("config").Group
+
+		for range 3 {
+			ctx, report := context.Background(), NewReport()
+			store := NewMapStore()
+			assert.NoError(t, g.Go(WithReport(WithStore(ctx, store), report), "t1"))
+			v, _ := store.Load("config")
+			assert.Equal(t, "config of t1", v)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+
+		report := NewReport()
+		assert.NoError(t, g.Go(WithReport(WithStore(context.Background(), NewMapStore()), report), "t2"))
+		res, _ := report.Result("config")
+		assert.False(t, res.Cached)
+		assert.Equal(t, int32(2), calls.Load())
+
+		report = NewReport()
+		assert.NoError(t, g.Go(WithReport(WithStore(context.Background(), NewMapStore()), report), "t2"))
+		res, _ = report.Result("config")
+		assert.True(t, res.Cached)
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		g := NewGroup().AddRunner(func() error {
+			if calls.Add(1) == 1 {
+				return errors.New("transient")
+			}
+			return nil
+		}).Key("a").WithCache(NewLRUCache(1), tenantKey, 0).Group
+		assert.Error(t, g.Go(context.Background(), "t"))
+		assert.NoError(t, g.Go(context.Background(), "t"))
+		assert.NoError(t, g.Go(context.Background(), "t"))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("bypass", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		g := NewGroup().AddRunner(func() error { calls.Add(1); return nil }).
+			WithCache(NewLRUCache(1), func(context.Context, any) string { return "" }, 0).Group
+		assert.NoError(t, g.Go(context.Background()))
+		assert.NoError(t, g.Go(context.Background()))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("own result", func(t *testing.T) {
+		t.Parallel()
+		cache := NewLRUCache(1)
+		g := NewGroup().AddRunner(func() error { return nil }).Key("a").WithCache(cache, tenantKey, 0).Group // stores nothing
+		store := NewMapStore()
+		store.Store("a", "stale")
+		assert.NoError(t, g.Go(WithStore(context.Background(), store), "t"))
+		v, ok := cache.Get("t")
+		assert.True(t, ok)
+		assert.Nil(t, v)
+
+		// without a store
+		fut := AddFutureRunner(NewGroup(), func() (int, error) { return 7, nil })
+		fut.Node().WithCache(NewLRUCache(1), func(context.Context, any) string { return "seven" }, 0)
+		for range 2 {
+			assert.NoError(t, fut.Node().Group.Go(context.Background()))
+			v, err := fut.Get()
+			assert.NoError(t, err)
+			assert.Equal(t, 7, v)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		f := func() error { return nil }
+		assert.PanicsWithValue(t, "cache and cache key func must not be nil", func() {
+			NewGroup().AddRunner(f).WithCache(nil, tenantKey, 0)
+		})
+		assert.PanicsWithValue(t, "cache and cache key func must not be nil", func() {
+			NewGroup().AddRunner(f).WithCache(NewLRUCache(1), nil, 0)
+		})
+		assert.ErrorIs(t, NewBuilder().AddRunner(f).WithCache(nil, nil, 0).Group.Err(), ErrInvalidGroup)
+	})
+}
+
+func TestLRUCache(t *testing.T) {
+	t.Parallel()
+
+	c := NewLRUCache(2)
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+	_, _ = c.Get("a") // b is least recently used
+	c.Set("c", 3, 0)
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	c.Set("d", 4, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	_, ok = c.Get("d")
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.Len())
+
+	assert.PanicsWithValue(t, "capacity must be positive", func() { NewLRUCache(0) })
+}
@@ -0,0 +1,90 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region CLONE
+
+func TestGroupClone(t *testing.T) {
+	t.Parallel()
+
+	var errTenant = errors.New("tenant error")
+	var tmpl = func(mu *sync.Mutex, order *[]string) *Group {
+		var record = func(key string) func() error {
+			return func() error {
+				mu.Lock()
+				defer mu.Unlock()
+				*order = append(*order, key)
+				return nil
+			}
+		}
+		return NewGroup(WithPrefix("tmpl")).
+			AddRunner(record("fetch")).Key("fetch").
+			AddRunner(record("audit")).Key("audit").Dep("fetch").
+			AddRunner(record("process")).Key("process").Dep("fetch").WeakDep("audit").
+			AddRunner(record("store")).Key("store").Dep("process").Group
+	}
+
+	t.Run("independent copy", func(t *testing.T) {
+		t.Parallel()
+		var mu sync.Mutex
+		var order []string
+		g := tmpl(&mu, &order)
+		c := g.Clone().WithOptions(WithPrefix("tenant"), WithTimeout(time.Second))
+		c.Node("fetch").WithFunc(func(context.Context) error { return errTenant }).WithRetry(1)
+		c.AddRunner(func() error { return nil }).Key("extra").Dep("store")
+
+		assert.ErrorIs(t, c.Go(context.Background()), errTenant)
+		assert.Empty(t, order)
+
+		// template is untouched
+		assert.Nil(t, g.Node("extra"))
+		assert.NotContains(t, g.Mermaid(nil), "tenant")
+		assert.NoError(t, g.Go(context.Background()))
+		assert.Equal(t, []string{"fetch", "audit", "process", "store"}, order)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		t.Parallel()
+		var mu sync.Mutex
+		var order []string
+		c := tmpl(&mu, &order).Clone().Remove("audit", RemoveDetach)
+		assert.Nil(t, c.Node("audit"))
+		levels, err := c.Levels()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]any{{"fetch"}, {"process"}, {"store"}}, levels)
+		assert.NoError(t, c.Validate())
+		assert.NoError(t, c.Go(context.Background()))
+		assert.Equal(t, []string{"fetch", "process", "store"}, order)
+
+		c.AddRunner(func() error { return nil }).Key("audit").Dep("store")
+		assert.NoError(t, c.Go(context.Background()))
+	})
+
+	t.Run("pending dependencies", func(t *testing.T) {
+		t.Parallel()
+		var f = func() error { return nil }
+		g := NewGroup().AddRunner(f).Key("a").Dep("later").Group
+		c := g.Clone()
+		c.AddRunner(f).Key("later")
+		assert.NoError(t, c.Go(context.Background()))
+		assert.ErrorIs(t, g.Go(context.Background()), ErrMissingDependency)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		assert.PanicsWithValue(t, `missing node "x"`, func() { NewGroup().Remove("x") })
+		assert.PanicsWithValue(t, "unsupported func type int", func() {
+			NewGroup().AddRunner(func() error { return nil }).WithFunc(1)
+		})
+	})
+}
@@ -0,0 +1,123 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region DEDUP
+
+func TestGroupDedup(t *testing.T) {
+	t.Parallel()
+
+	var sameKey = func(context.Context, any) string { return "config" }
+
+	t.Run("shared execution", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		g := NewGroup().
+			AddAutoTask(func(ctx context.Context) (any, error) {
+				calls.Add(1)
+				time.Sleep(200 * time.Millisecond)
+				return "config", nil
+			}).Key("config").Dedup(sameKey).Group
+
+		var wg sync.WaitGroup
+		stores := make([]Storer, 5)
+		for i := range stores {
+			stores[i] = NewMapStore()
+			wg.Go(func() { assert.NoError(t, g.Go(WithStore(context.Background(), stores[i]))) })
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), calls.Load())
+		for _, store := range stores {
+			v, ok := store.Load("config")
+			assert.True(t, ok)
+			assert.Equal(t, "config", v)
+		}
+
+		// finished executions are not shared
+		assert.NoError(t, g.Go(WithStore(context.Background(), NewMapStore())))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("shared error", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		errLoad := errors.New("load failed")
+		g := NewGroup().AddRunner(func() error {
+			calls.Add(1)
+			time.Sleep(100 * time.Millisecond)
+			return errLoad
+		}).Key("config").Dedup(sameKey).Group
+
+		var wg sync.WaitGroup
+		for range 3 {
+			wg.Go(func() { assert.ErrorIs(t, g.Go(context.Background()), errLoad) })
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("leader cancelled", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		g := NewGroup().AddTask(func(ctx context.Context) error {
+			calls.Add(1)
+			select {
+			case <-time.After(200 * time.Millisecond):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}).Key("config").Dedup(sameKey).Group
+
+		leaderCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		var wg sync.WaitGroup
+		wg.Go(func() { assert.ErrorIs(t, g.Go(leaderCtx), context.DeadlineExceeded) })
+		time.Sleep(20 * time.Millisecond)
+		wg.Go(func() { assert.NoError(t, g.Go(context.Background())) }) // follower is not affected
+		wg.Wait()
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("all waiters left", func(t *testing.T) {
+		t.Parallel()
+		cancelled := make(chan struct{})
+		g := NewGroup().AddTask(func(ctx context.Context) error {
+			<-ctx.Done()
+			close(cancelled)
+			return ctx.Err()
+		}).Key("config").Dedup(sameKey).Group
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, g.Go(ctx), context.DeadlineExceeded)
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			assert.Fail(t, "execution not cancelled")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		f := func() error { return nil }
+		assert.PanicsWithValue(t, "dedup key func must not be nil", func() {
+			NewGroup().AddRunner(f).Dedup(nil)
+		})
+		assert.PanicsWithValue(t, "dedup key func must not be nil", func() {
+			NewGroupOf[int]().AddRunner(f).Dedup(nil)
+		})
+		assert.ErrorIs(t, NewBuilder().AddRunner(f).Dedup(nil).Group.Err(), ErrInvalidGroup)
+	})
+}
@@ -0,0 +1,106 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+func TestLoadDefinition(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var order []string
+	record := func(key string) func() error {
+		return func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, key)
+			return nil
+		}
+	}
+	reg := NewRegistry().
+		Register("a", record("a")).
+		Register("b", record("b")).
+		Register("c", func(ctx context.Context) error { return record("c")() }).
+		Register("fail", func() error { return errors.New("failed") }).
+		RegisterCondition("never", func(context.Context, any) bool { return false })
+
+	t.Run("yaml definition in any order", func(t *testing.T) {
+		g, err := Load([]byte(`
+prefix: pipeline
+limit: 2
+timeout: 1s
+nodes:
+  - key: c
+    func: c
+    deps: [b]
+    retry: 1
+    timeout: 100ms
+  - key: b
+    func: b
+    deps: [a]
+  - key: a
+    func: a
+  - key: skipped
+    func: fail
+    condition: never
+`), reg)
+		assert.Nil(t, err)
+		assert.Nil(t, g.Go(context.Background()))
+		assert.Equal(t, []string{"a", "b", "c"}, order)
+	})
+
+	t.Run("json definition", func(t *testing.T) {
+		g, err := Load([]byte(`{
+			"nodes": [
+				{"key": "f", "func": "fail", "silent_fail": true},
+				{"key": "x", "func": "a", "weak_deps": ["f"]}
+			]
+		}`), reg)
+		assert.Nil(t, err)
+		assert.Nil(t, g.Go(context.Background()))
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		_, err := Load([]byte(`
+nodes:
+  - key: a
+    func: missing
+  - key: b
+    func: b
+    deps: [x]
+    condition: unknown
+  - key: b
+    func: b
+`), reg)
+		assert.True(t, errors.Is(err, ErrUnknownFunc))
+		assert.True(t, errors.Is(err, ErrMissingDependency))
+		assert.True(t, errors.Is(err, ErrUnknownCondition))
+		assert.True(t, errors.Is(err, ErrDuplicateKey))
+		assert.Contains(t, err.Error(), `node "a": unknown func "missing"`)
+		assert.Contains(t, err.Error(), `node "b": missing dependency "x"`)
+
+		_, err = Load([]byte(`
+nodes:
+  - {key: a, func: a, deps: [c]}
+  - {key: b, func: b, deps: [a]}
+  - {key: c, func: c, deps: [b]}
+`), reg)
+		assert.True(t, errors.Is(err, ErrCycle))
+
+		_, err = Load([]byte(`
+nodes:
+  - {key: a, func: a, retries: 1}
+`), reg)
+		assert.True(t, errors.Is(err, ErrInvalidDefinition))
+
+		_, err = Load([]byte(`{"nodes": [{"key": "a", "func": "a", "timeout": "soon"}]}`), reg)
+		assert.True(t, errors.Is(err, ErrInvalidDefinition))
+	})
+}
@@ -0,0 +1,158 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region FUTURE
+
+func TestFuture(t *testing.T) {
+	t.Parallel()
+
+	t.Run("typed results", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup()
+		user := AddFutureTask(g, func(context.Context) (string, error) { return "alice", nil })
+		orders := AddFutureRunner(g, func() ([]int, error) { return []int{1, 2}, nil })
+		total := AddFuture(g, func(context.Context, any) (int, error) {
+			o, err := orders.Get() // upstream is resolved
+			return len(o), err
+		})
+		total.Node().Dep(orders)
+
+		_, err := user.Get()
+		assert.ErrorIs(t, err, ErrNotResolved) // no run yet
+
+		assert.NoError(t, g.Go(context.Background()))
+		u, err := user.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", u)
+		n, err := total.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+	})
+
+	t.Run("during run", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup()
+		fast := AddFutureRunner(g, func() (int, error) { return 1, nil })
+		g.AddRunner(func() error { time.Sleep(200 * time.Millisecond); return nil })
+
+		done := make(chan error)
+		go func() { done <- g.Go(context.Background()) }()
+		time.Sleep(50 * time.Millisecond)
+		v, err := fast.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, v)
+		select {
+		case <-done:
+			assert.Fail(t, "group finished before slow node")
+		default:
+		}
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("errors and unresolved", func(t *testing.T) {
+		t.Parallel()
+		errFailed := errors.New("failed")
+		g := NewGroup()
+		failed := AddFutureRunner(g, func() (int, error) { return 0, errFailed })
+		blocked := AddFutureRunner(g, func() (int, error) { return 1, nil })
+		blocked.Node().Dep(failed)
+		skipped := AddFutureRunner(g, func() (int, error) { return 1, nil })
+		skipped.Node().SkipIf(true)
+
+		assert.ErrorIs(t, g.Go(context.Background()), errFailed)
+		_, err := failed.Get()
+		assert.ErrorIs(t, err, errFailed)
+		_, err = blocked.Get()
+		assert.ErrorIs(t, err, ErrNotResolved)
+		_, err = skipped.Get()
+		assert.ErrorIs(t, err, ErrNotResolved)
+	})
+
+	t.Run("stored", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup()
+		fut := AddFutureRunner(g, func() (int, error) { return 7, nil })
+		fut.Node().Key("seven")
+		ctx := WithStore(context.Background(), NewMapStore())
+		assert.NoError(t, g.Go(ctx))
+		v, ok := Fetch[int](ctx, "seven")
+		assert.True(t, ok)
+		assert.Equal(t, 7, v)
+		assert.Same(t, fut.Node(), g.Node(fut))
+	})
+
+	t.Run("cache hit", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		g := NewGroup()
+		fut := AddFutureRunner(g, func() (int, error) { calls.Add(1); return 7, nil })
+		fut.Node().WithCache(NewLRUCache(1), func(context.Context, any) string { return "seven" }, 0)
+
+		for range 2 {
+			assert.NoError(t, g.Go(WithStore(context.Background(), NewMapStore())))
+			v, err := fut.Get()
+			assert.NoError(t, err)
+			assert.Equal(t, 7, v)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("dedup follower", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		g := NewGroup()
+		fut := AddFuture(g, func(context.Context, any) (int, error) {
+			calls.Add(1)
+			time.Sleep(100 * time.Millisecond)
+			return 7, nil
+		})
+		fut.Node().Dedup(func(context.Context, any) string { return "seven" }).
+			WithAfterFunc(func(_ context.Context, shared any, err error) error {
+				if shared == "leader" {
+					time.Sleep(100 * time.Millisecond) // the follower resolves the future first
+				}
+				return err
+			})
+
+		leader := make(chan error)
+		go func() { leader <- g.Go(context.Background(), "leader") }()
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, g.Go(context.Background(), "follower"))
+		v, err := fut.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, 7, v)
+		assert.NoError(t, <-leader)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("clone and include", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup()
+		fut := AddFutureRunner(g, func() (int, error) { return 7, nil })
+
+		store := NewMapStore()
+		assert.NoError(t, g.Clone().Go(WithStore(context.Background(), store)))
+		v, _ := store.Load(fut)
+		assert.Equal(t, 7, v) // clone results are read from the store
+		_, err := fut.Get()
+		assert.ErrorIs(t, err, ErrNotResolved) // the template future is not shared
+
+		store = NewMapStore()
+		assert.NoError(t, NewGroup().Include(g, "inner").Go(WithStore(context.Background(), store)))
+		v, _ = store.Load(NS("inner", fut))
+		assert.Equal(t, 7, v)
+		_, err = fut.Get()
+		assert.ErrorIs(t, err, ErrNotResolved)
+	})
+}
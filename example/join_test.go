@@ -0,0 +1,153 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region JOIN
+func TestGoJoinErrors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	errA, errB := errors.New("a failed"), errors.New("b failed")
+
+	t.Run("run all", func(t *testing.T) {
+		t.Parallel()
+		var done atomic.Int32
+		err := Go(ctx, Opts(WithJoinErrors),
+			func() error { return errA },
+			func() error { time.Sleep(20 * time.Millisecond); done.Add(1); return nil },
+			func() error { time.Sleep(10 * time.Millisecond); return errB },
+			func() error { time.Sleep(30 * time.Millisecond); done.Add(1); return nil },
+		)
+		assert.Equal(t, int32(2), done.Load()) // not cancelled by failures
+		assert.ErrorIs(t, err, errA)
+		assert.ErrorIs(t, err, errB)
+
+		var resErr *ResultsError
+		assert.ErrorAs(t, err, &resErr)
+		assert.Equal(t, []int{0, 2}, resErr.Failed())
+		assert.EqualError(t, resErr.Errs[0], "func #0: a failed")
+		assert.EqualError(t, resErr.Errs[2], "func #2: b failed")
+		assert.Equal(t, "func #0: a failed\nfunc #2: b failed", err.Error())
+	})
+
+	t.Run("no errors", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, Go(ctx, Opts(WithJoinErrors), func() error { return nil }, func() error { return nil }))
+		ok, err := TryGo(ctx, Opts(WithJoinErrors, WithLimit(2)), func() error { return nil })
+		assert.True(t, ok)
+		assert.NoError(t, err)
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		t.Parallel()
+		var fs []func() error
+		var ran atomic.Int32
+		for i := range 10 {
+			fs = append(fs, func() error {
+				ran.Add(1)
+				if i%2 == 0 {
+					return fmt.Errorf("err %d", i)
+				}
+				return nil
+			})
+		}
+		err := Go(ctx, Opts(WithLimit(1), WithErrorThreshold(2)), fs...)
+		assert.ErrorIs(t, err, ErrErrorThreshold)
+		assert.Equal(t, int32(3), ran.Load()) // #0 fails, #1 succeeds, #2 fails
+		assert.Contains(t, err.Error(), "error threshold reached: 2 failures")
+
+		var resErr *ResultsError
+		assert.ErrorAs(t, err, &resErr)
+		assert.NoError(t, resErr.Errs[1])
+		assert.EqualError(t, resErr.Errs[2], "func #2: err 2")
+		for _, e := range resErr.Errs[3:] {
+			assert.ErrorIs(t, e, ErrNotRun)
+		}
+	})
+
+	t.Run("threshold cancels running funcs", func(t *testing.T) {
+		t.Parallel()
+		var cancelled atomic.Bool
+		started := make(chan struct{}) // the failing funcs wait for the third one to start
+		err := GoCtx(ctx, Opts(WithErrorThreshold(2)),
+			func(context.Context) error { <-started; return errA },
+			func(context.Context) error { <-started; return errB },
+			func(ctx context.Context) error {
+				close(started)
+				select {
+				case <-ctx.Done():
+					cancelled.Store(true)
+					return ctx.Err()
+				case <-time.After(time.Second):
+					return nil
+				}
+			},
+		)
+		assert.ErrorIs(t, err, ErrErrorThreshold)
+		assert.True(t, cancelled.Load())
+	})
+
+	t.Run("try go", func(t *testing.T) {
+		t.Parallel()
+		var done atomic.Bool
+		ok, err := TryGoCtx(ctx, Opts(WithLimit(2), WithJoinErrors),
+			func(context.Context) error { return errA },
+			func(context.Context) error { time.Sleep(10 * time.Millisecond); done.Store(true); return nil },
+		)
+		assert.True(t, ok)
+		assert.True(t, done.Load())
+		assert.ErrorIs(t, err, errA)
+	})
+
+	t.Run("error collector and after func", func(t *testing.T) {
+		t.Parallel()
+		errC := make(chan error, 2)
+		var joined error
+		err := GoCtx(ctx, Opts(WithJoinErrors, WithErrorCollector(errC),
+			WithAfterFunc(func(_ context.Context, err error) error { joined = err; return nil })),
+			func(context.Context) error { return errA },
+			func(context.Context) error { return errB },
+		)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, joined, errA)
+		assert.ErrorIs(t, joined, errB)
+		assert.Len(t, errC, 2)
+	})
+
+	t.Run("panic fast fail", func(t *testing.T) {
+		t.Parallel()
+		err := Go(ctx, Opts(WithLimit(1), WithJoinErrors, WithPanicPolicy(PanicFastFail)),
+			func() error { panic("oops") },
+			func() error { return nil },
+		)
+		assert.ErrorIs(t, err, ErrPanic)
+		var resErr *ResultsError
+		assert.ErrorAs(t, err, &resErr)
+		assert.ErrorIs(t, resErr.Errs[1], ErrNotRun)
+	})
+
+	t.Run("results", func(t *testing.T) {
+		t.Parallel()
+		res, err := GoResults(ctx, Opts(WithJoinErrors),
+			func(context.Context) (int, error) { return 0, errA },
+			func(context.Context) (int, error) { time.Sleep(10 * time.Millisecond); return 2, nil },
+		)
+		assert.ErrorIs(t, err, errA)
+		assert.Equal(t, []int{0, 2}, res)
+	})
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		t.Parallel()
+		assert.PanicsWithValue(t, "error threshold must be positive", func() { Opts(WithErrorThreshold(0)) })
+	})
+}
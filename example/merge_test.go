@@ -0,0 +1,146 @@
+package group
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region MERGE
+
+func TestGroupMerge(t *testing.T) {
+	t.Parallel()
+
+	var record = func(mu *sync.Mutex, order *[]string, key string) func() error {
+		return func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			*order = append(*order, key)
+			return nil
+		}
+	}
+
+	t.Run("include", func(t *testing.T) {
+		t.Parallel()
+		var mu sync.Mutex
+		var order []string
+		// billing depends on the order part by qualified key, resolved after merging
+		billing := NewGroup(WithPrefix("billing")).
+			AddRunner(record(&mu, &order, "charge")).Key("charge").Dep(NS("orders", "create")).
+			AddRunner(record(&mu, &order, "invoice")).Key("invoice").Dep("charge").Group
+		orders := NewGroup(WithPrefix("orders")).
+			AddRunner(record(&mu, &order, "create")).Key("create").Group
+
+		g := NewGroup().Include(orders, "orders").Include(billing, "billing")
+		assert.NotNil(t, g.Node(NS("billing", "invoice")))
+		assert.Nil(t, g.Node("invoice"))
+		assert.NoError(t, g.Go(context.Background()))
+		assert.Equal(t, []string{"create", "charge", "invoice"}, order)
+
+		levels, err := g.Levels()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]any{{NS("orders", "create")}, {NS("billing", "charge")}, {NS("billing", "invoice")}}, levels)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		t.Parallel()
+		var mu sync.Mutex
+		var order []string
+		a := NewGroup(WithPrefix("a"), WithLimit(2), WithTimeout(time.Second)).AddRunner(record(&mu, &order, "a")).Key("x").Group
+		b := NewGroup(WithPrefix("b"), WithLimit(3), WithTimeout(2*time.Second)).AddRunner(record(&mu, &order, "b")).Key("x").Dep(NS("a", "x")).Group
+
+		_, err := Merge(ConflictError, a, b)
+		assert.ErrorIs(t, err, ErrInvalidGroup)
+		assert.ErrorContains(t, err, `include "b": invalid group: conflicting options: limit 2 != 3, timeout 1s != 2s`)
+
+		g, err := Merge(ConflictCombine, a, b)
+		assert.NoError(t, err)
+		assert.Contains(t, g.Mermaid(nil), `title: "Group: a+b [limit=5 | timeout=2s]"`)
+		assert.NoError(t, g.Go(context.Background()))
+		assert.Equal(t, []string{"a", "b"}, order)
+
+		g, err = Merge(ConflictKeep, a, b)
+		assert.NoError(t, err)
+		assert.Contains(t, g.Mermaid(nil), "[limit=2 | timeout=1s]")
+
+		g, err = Merge(ConflictOverride, a, b)
+		assert.NoError(t, err)
+		assert.Contains(t, g.Mermaid(nil), "[limit=3 | timeout=2s]")
+	})
+
+	t.Run("default prefixes", func(t *testing.T) {
+		t.Parallel()
+		var mu sync.Mutex
+		var order []string
+		a := NewGroup().AddRunner(record(&mu, &order, "a")).Key("x").Group
+		b := NewGroup().AddRunner(record(&mu, &order, "b")).Key("x").Dep(NS("anonymous#0", "x")).Group
+		g, err := Merge(ConflictError, a, b)
+		assert.NoError(t, err)
+		assert.NotNil(t, g.Node(NS("anonymous#0", "x")))
+		assert.NotNil(t, g.Node(NS("anonymous#1", "x")))
+		assert.NoError(t, g.Go(context.Background()))
+		assert.Equal(t, []string{"a", "b"}, order)
+	})
+
+	t.Run("dedup not shared", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		release := make(chan struct{})
+		inner := NewGroup(WithPrefix("inner")).AddRunner(func() error {
+			calls.Add(1)
+			<-release
+			return nil
+		}).Key("x").Dedup(func(context.Context, any) string { return "same" }).Group
+		g := NewGroup().Include(inner, "inner")
+
+		var wg sync.WaitGroup
+		for _, run := range []*Group{inner, g} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, run.Go(context.Background()))
+			}()
+		}
+		assert.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+	})
+
+	t.Run("combined interceptors", func(t *testing.T) {
+		t.Parallel()
+		var mu sync.Mutex
+		var order []string
+		var pre = func(key string) PreFunc {
+			return func(context.Context) error { return record(&mu, &order, key)() }
+		}
+		var after = func(key string) AfterFunc {
+			return func(_ context.Context, err error) error { record(&mu, &order, key)(); return err }
+		}
+		a := NewGroup(WithPrefix("a"), WithPreFunc(pre("pre a")), WithAfterFunc(after("after a"))).
+			AddRunner(record(&mu, &order, "a")).Key("x").Group
+		b := NewGroup(WithPrefix("b"), WithPreFunc(pre("pre b")), WithAfterFunc(after("after b"))).
+			AddRunner(record(&mu, &order, "b")).Key("x").Dep(NS("a", "x")).Group
+		g, err := Merge(ConflictCombine, a, b)
+		assert.NoError(t, err)
+		assert.NoError(t, g.Go(context.Background()))
+		assert.Equal(t, []string{"pre a", "pre b", "a", "b", "after a", "after b"}, order)
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		t.Parallel()
+		var f = func() error { return nil }
+		a := NewGroup().AddRunner(f).Key("x").Group
+		assert.PanicsWithValue(t, `invalid group: include "": duplicate node key "x"`, func() {
+			NewGroup().AddRunner(f).Key("x").Group.Include(a, "")
+		})
+		err := NewBuilder().AddRunner(f).Key(NS("a", "x")).Group.Include(a, "a").Err()
+		assert.ErrorIs(t, err, ErrInvalidGroup)
+		assert.ErrorContains(t, err, `duplicate node key "a/x"`)
+	})
+}
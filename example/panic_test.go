@@ -0,0 +1,151 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+func TestGroupGoPanicPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("panic recovered as error by default", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		err := NewGroup().
+			AddRunner(func() error { panic("boom") }).Key("a").
+			Go(ctx)
+
+		var p *PanicError
+		assert.True(t, errors.As(err, &p))
+		assert.True(t, errors.Is(err, ErrPanic))
+		assert.Equal(t, "boom", p.Value)
+		assert.NotEmpty(t, p.Stack)
+		assert.Contains(t, p.Location(), "panic_test.go")
+		assert.Contains(t, err.Error(), "boom")
+	})
+
+	t.Run("panic error value unwraps", func(t *testing.T) {
+		t.Parallel()
+		ctx, cause := context.Background(), errors.New("cause")
+
+		err := NewGroup().
+			AddRunner(func() error { panic(cause) }).Key("a").
+			Go(ctx)
+
+		assert.True(t, errors.Is(err, ErrPanic))
+		assert.True(t, errors.Is(err, cause))
+	})
+
+	t.Run("interceptors, conditions and rollbacks are protected", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		err := NewGroup().
+			AddRunner(func() error { return nil }).Key("a").
+			WithAfterFunc(func(context.Context, any, error) error { panic("after") }).
+			AddRunner(func() error { return nil }).Key("b").
+			WithCondition(func(context.Context, any) bool { panic("cond") }).
+			AddRunner(func() error { return errors.New("c failed") }).Key("c").
+			WithRollback(func(context.Context, any, error) error { panic("rollback") }).
+			Go(ctx)
+
+		assert.True(t, errors.Is(err, ErrPanic))
+		assert.Contains(t, err.Error(), "after")
+		assert.Contains(t, err.Error(), "cond")
+		assert.Contains(t, err.Error(), "rollback")
+
+		err = NewGroup(WithPreFunc(func(context.Context) error { panic("pre") })).
+			AddRunner(func() error { return nil }).
+			Go(ctx)
+		assert.True(t, errors.Is(err, ErrPanic))
+
+		err = NewGroup(WithAfterFunc(func(context.Context, error) error { panic("after") })).
+			AddRunner(func() error { return nil }).
+			Go(ctx)
+		assert.True(t, errors.Is(err, ErrPanic))
+	})
+
+	t.Run("fast-fail on panic", func(t *testing.T) {
+		t.Parallel()
+		ctx, s := context.Background(), time.Now()
+		var ran bool
+
+		err := NewGroup(WithPanicPolicy(PanicFastFail)).
+			AddRunner(func() error { panic("boom") }).Key("a").
+			AddRunner(func() error { time.Sleep(100 * time.Millisecond); return nil }).Key("b").
+			AddRunner(func() error { ran = true; return nil }).Dep("b"). // blocked since group halted
+			Go(ctx)
+
+		assert.True(t, errors.Is(err, ErrPanic))
+		assert.False(t, ran)
+		assert.Less(t, time.Since(s), 200*time.Millisecond)
+	})
+
+	t.Run("re-panic after cleanup", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		var rolledBack, after bool
+
+		g := NewGroup(WithPanicPolicy(PanicRepanic), WithAfterFunc(func(_ context.Context, err error) error {
+			after = true
+			return err
+		})).
+			AddRunner(func() error { return nil }).Key("a").
+			WithRollback(func(context.Context, any, error) error { rolledBack = true; return nil }).
+			AddRunner(func() error { panic("boom") }).Key("b").Dep("a").Group
+
+		func() {
+			defer func() {
+				p, ok := recover().(*PanicError)
+				assert.True(t, ok)
+				assert.Equal(t, "boom", p.Value)
+			}()
+			_ = g.Go(ctx)
+		}()
+		assert.True(t, rolledBack)
+		assert.True(t, after)
+	})
+
+	t.Run("custom panic handler", func(t *testing.T) {
+		t.Parallel()
+		ctx, handled := context.Background(), errors.New("handled")
+		var value any
+
+		err := NewGroup(WithPanicHandler(func(_ context.Context, p *PanicError) error {
+			value = p.Value
+			return handled
+		})).
+			AddRunner(func() error { panic("boom") }).Key("a").
+			Go(ctx)
+
+		assert.Equal(t, handled, err)
+		assert.Equal(t, "boom", value)
+
+		err = NewGroup(WithPanicHandler(func(context.Context, *PanicError) error { return nil })).
+			AddRunner(func() error { panic("swallowed") }).Key("a").
+			Go(ctx)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGoPanicPolicy(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	err := Go(ctx, Opts(), func() error { panic("boom") })
+	assert.True(t, errors.Is(err, ErrPanic))
+
+	assert.Panics(t, func() {
+		_ = Go(ctx, Opts(WithPanicPolicy(PanicRepanic)), func() error { panic("boom") })
+	})
+
+	err = Go(ctx, Opts(WithPanicHandler(func(context.Context, *PanicError) error { return nil })), func() error { panic("boom") })
+	assert.Nil(t, err)
+}
@@ -0,0 +1,190 @@
+package group
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region PERSIST
+type persistKey struct{ Name string }
+type persistValue struct {
+	ID   int
+	Tags []string
+}
+
+func init() {
+	gob.Register(persistKey{})
+	gob.Register(persistValue{})
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	for name, newStore := range map[string]func() Storer{
+		"map":     func() Storer { return NewMapStore() },
+		"sharded": func() Storer { return NewShardedStore(4) },
+		"syncMap": func() Storer { return NewSyncMapStore() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			store := newStore()
+			ctx := WithStore(context.Background(), store)
+			err := NewGroup().
+				AddTask(func(ctx context.Context) error { Store(ctx, 1); return nil }).Key("a").
+				AddTask(func(ctx context.Context) error {
+					Store(ctx, persistValue{ID: 2, Tags: []string{"x"}})
+					return nil
+				}).Key(persistKey{"b"}).Dep("a").Group.Go(ctx)
+			assert.NoError(t, err)
+
+			var buf bytes.Buffer
+			assert.NoError(t, Snapshot(&buf, store.(Ranger)))
+
+			restored := NewMapStore()
+			assert.NoError(t, Restore(&buf, restored))
+			v, ok := restored.Load("a")
+			assert.True(t, ok)
+			assert.Equal(t, 1, v)
+			v, ok = restored.Load(persistKey{"b"})
+			assert.True(t, ok)
+			assert.Equal(t, persistValue{ID: 2, Tags: []string{"x"}}, v)
+		})
+	}
+
+	t.Run("unregistered type", func(t *testing.T) {
+		t.Parallel()
+		type local struct{ X int }
+		store := NewMapStore()
+		store.Store("a", local{1})
+		var buf bytes.Buffer
+		assert.ErrorContains(t, Snapshot(&buf, store), "snapshot store")
+	})
+
+	t.Run("corrupted input", func(t *testing.T) {
+		t.Parallel()
+		assert.ErrorContains(t, Restore(bytes.NewReader([]byte("garbage")), NewMapStore()), "restore store")
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+	filename := filepath.Join(t.TempDir(), "store.gob")
+
+	// first run
+	store, err := NewFileStore(filename)
+	assert.NoError(t, err)
+	err = NewGroup().
+		AddTask(func(ctx context.Context) error { Store(ctx, 1); return nil }).Key("a").
+		AddTask(func(ctx context.Context) error {
+			Store(ctx, persistValue{ID: 2})
+			return nil
+		}).Key(persistKey{"b"}).Dep("a").Group.Go(WithStore(context.Background(), store))
+	assert.NoError(t, err)
+	store.Store("a", 10) // overwrite, latest record wins
+	assert.NoError(t, store.Close())
+
+	// reload in a later run
+	store, err = NewFileStore(filename)
+	assert.NoError(t, err)
+	defer store.Close()
+	v, ok := store.Load("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, v)
+	v, ok = store.Load(persistKey{"b"})
+	assert.True(t, ok)
+	assert.Equal(t, persistValue{ID: 2}, v)
+
+	var got int
+	err = NewGroup().
+		AddTask(func(ctx context.Context) error {
+			got, _ = Fetch[int](ctx, "a")
+			return nil
+		}).Group.Go(WithStore(context.Background(), store))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, got)
+
+	// write errors are reported
+	type local struct{ X int }
+	store.Store("c", local{1})
+	assert.ErrorContains(t, store.Err(), "store c")
+	v, ok = store.Load("c") // still readable in memory
+	assert.True(t, ok)
+	assert.Equal(t, local{1}, v)
+
+	t.Run("corrupted file", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "corrupted.gob")
+		assert.NoError(t, os.WriteFile(filename, []byte{0x01, 'x'}, 0644))
+		_, err := NewFileStore(filename)
+		assert.ErrorContains(t, err, "load store file")
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "write_error.gob")
+		store, err := NewFileStore(filename)
+		assert.NoError(t, err)
+		type local struct{ X int }
+		store.Store("a", persistValue{ID: 1})
+		store.Store("b", local{2}) // unregistered
+		store.Store("c", persistValue{ID: 3})
+		assert.ErrorContains(t, store.Close(), "store b")
+
+		store, err = NewFileStore(filename)
+		assert.NoError(t, err)
+		defer store.Close()
+		v, ok := store.Load("c")
+		assert.True(t, ok)
+		assert.Equal(t, persistValue{ID: 3}, v)
+		_, ok = store.Load("b")
+		assert.False(t, ok)
+	})
+
+	t.Run("torn trailing record", func(t *testing.T) {
+		t.Parallel()
+		filename := filepath.Join(t.TempDir(), "torn.gob")
+		store, err := NewFileStore(filename)
+		assert.NoError(t, err)
+		store.Store("a", 1)
+		store.Store("b", 2)
+		assert.NoError(t, store.Close())
+		info, err := os.Stat(filename)
+		assert.NoError(t, err)
+
+		// interrupted write
+		file, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND, 0644)
+		assert.NoError(t, err)
+		_, err = file.Write([]byte{0x40, 'g', 'a', 'r', 'b', 'a', 'g', 'e'})
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+
+		store, err = NewFileStore(filename)
+		assert.NoError(t, err)
+		v, ok := store.Load("b")
+		assert.True(t, ok)
+		assert.Equal(t, 2, v)
+		torn, err := os.Stat(filename)
+		assert.NoError(t, err)
+		assert.Equal(t, info.Size(), torn.Size()) // truncated to the last complete record
+
+		// appends after the truncation are loaded with the previous sessions
+		store.Store("c", 3)
+		assert.NoError(t, store.Close())
+		store, err = NewFileStore(filename)
+		assert.NoError(t, err)
+		defer store.Close()
+		for key, want := range map[string]int{"a": 1, "b": 2, "c": 3} {
+			v, ok := store.Load(key)
+			assert.True(t, ok)
+			assert.Equal(t, want, v)
+		}
+	})
+}
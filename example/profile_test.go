@@ -0,0 +1,53 @@
+package group
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+func spin(d time.Duration) {
+	for s := time.Now(); time.Since(s) < d; {
+	}
+}
+
+func TestGroupGoPprofLabels(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	labels := make(map[string]string)
+	err := NewGroup(WithPrefix("labeled"), WithPprofLabels).
+		AddTask(func(ctx context.Context) error {
+			pprof.ForLabels(ctx, func(key, value string) bool {
+				labels[key] = value
+				return true
+			})
+			return nil
+		}).Key("a").
+		Go(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{LabelGroup: "labeled", LabelNode: "a"}, labels)
+}
+
+func TestGroupProfile(t *testing.T) {
+	ctx := context.Background()
+
+	rp, err := NewGroup(WithPrefix("profiled")).
+		AddRunner(func() error { spin(300 * time.Millisecond); return nil }).Key("busy").
+		AddRunner(func() error { time.Sleep(100 * time.Millisecond); return nil }).Key("idle").
+		Group.Profile(ctx)
+
+	assert.Nil(t, err)
+	assert.Nil(t, rp.Err)
+	assert.Greater(t, rp.Nodes["busy"], rp.Nodes["idle"])
+	assert.Greater(t, rp.Nodes["busy"], time.Duration(0))
+	assert.GreaterOrEqual(t, rp.Total, rp.Nodes["busy"])
+	assert.NotEmpty(t, rp.Raw)
+	assert.Contains(t, rp.String(), "busy")
+}
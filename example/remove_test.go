@@ -0,0 +1,97 @@
+package group
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region REMOVE
+
+func TestGroupRemove(t *testing.T) {
+	t.Parallel()
+
+	var f = func() error { return nil }
+	// a -> b -> c, a -> d -(weak)-> e, c -> e
+	var build = func() *Group {
+		return NewGroup().
+			AddRunner(f).Key("a").
+			AddRunner(f).Key("b").Dep("a").
+			AddRunner(f).Key("c").Dep("b").
+			AddRunner(f).Key("d").Dep("a").
+			AddRunner(f).Key("e").WeakDep("d").Dep("c").Group
+	}
+	var levels = func(t *testing.T, g *Group) [][]any {
+		levels, err := g.Levels()
+		assert.NoError(t, err)
+		return levels
+	}
+
+	t.Run("detach", func(t *testing.T) {
+		t.Parallel()
+		g := build().Remove("b", RemoveDetach)
+		assert.Equal(t, [][]any{{"a", "c"}, {"d"}, {"e"}}, levels(t, g))
+		assert.NoError(t, g.Go(context.Background()))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+		assert.PanicsWithValue(t, `node "b" has dependents "c"`, func() { build().Remove("b") }) // default policy
+		assert.PanicsWithValue(t, `node "b" has dependents "c"`, func() { build().Remove("b", RemoveError) })
+		assert.ErrorContains(t, NewBuilder().AddRunner(f).Key("a").AddRunner(f).Dep("a").Group.Remove("a", RemoveError).Err(), `node "a" has dependents "node_1"`)
+		g := build().Remove("e", RemoveError)
+		assert.Equal(t, [][]any{{"a"}, {"b", "d"}, {"c"}}, levels(t, g))
+	})
+
+	t.Run("reattach", func(t *testing.T) {
+		t.Parallel()
+		g := build().Remove("b", RemoveReattach).Remove("d", RemoveReattach)
+		assert.Equal(t, [][]any{{"a"}, {"c"}, {"e"}}, levels(t, g))
+		// e keeps a single (strong) dependency on a through c, the weak edge from d is reattached to a
+		m := g.Mermaid(nil)
+		assert.Contains(t, m, "n0 --> n1")
+		assert.Contains(t, m, "n1 --> n2")
+		assert.Contains(t, m, "n0 -.->|weak| n2")
+		assert.NoError(t, g.Go(context.Background()))
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		t.Parallel()
+		g := build().Remove("b", RemoveCascade)
+		assert.Equal(t, [][]any{{"a"}, {"d"}}, levels(t, g))
+		assert.Nil(t, g.Node("e"))
+		assert.NoError(t, g.Go(context.Background()))
+	})
+
+	t.Run("forward dependency", func(t *testing.T) {
+		t.Parallel()
+		// x depends on b before b is added
+		var forward = func() *Group {
+			return NewGroup().
+				AddRunner(f).Key("a").
+				AddRunner(f).Key("x").Dep("b").
+				AddRunner(f).Key("b").Dep("a").Group
+		}
+		assert.PanicsWithValue(t, `node "b" has dependents "x"`, func() { forward().Remove("b") })
+
+		g := forward().Remove("b", RemoveCascade)
+		assert.Nil(t, g.Node("x"))
+		assert.NoError(t, g.Go(context.Background()))
+
+		g = forward().Remove("b", RemoveReattach)
+		assert.Equal(t, [][]any{{"a"}, {"x"}}, levels(t, g))
+		assert.NoError(t, g.Go(context.Background()))
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		t.Parallel()
+		var replaced bool
+		g := build().Replace("c", func(context.Context) error { replaced = true; return nil })
+		assert.NoError(t, g.Go(context.Background()))
+		assert.True(t, replaced)
+		assert.PanicsWithValue(t, `missing node "x"`, func() { g.Replace("x", f) })
+	})
+}
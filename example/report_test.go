@@ -0,0 +1,55 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+func TestGroupGoReport(t *testing.T) {
+	t.Parallel()
+	rep := NewReport()
+	ctx := WithReport(context.Background(), rep)
+
+	var attempts int
+	err := NewGroup().
+		AddRunner(func() error { return nil }).Key("ok").
+		AddRunner(func() error {
+			if attempts++; attempts < 3 {
+				return errors.New("flaky")
+			}
+			return nil
+		}).Key("retried").WithRetry(2).
+		AddRunner(func() error { return errors.New("failed") }).Key("failed").
+		AddRunner(func() error { return nil }).Key("blocked").Dep("failed").
+		AddRunner(func() error { return nil }).Key("skipped").SkipIf(true).
+		AddRunner(func() error { time.Sleep(50 * time.Millisecond); return nil }).Key("timeout").WithTimeout(10 * time.Millisecond).
+		AddRunner(func() error { return nil }).Key("rollback").
+		WithRollback(func(context.Context, any, error) error { return nil }).
+		Go(ctx)
+	assert.NotNil(t, err)
+
+	for key, status := range map[string]NodeStatus{
+		"ok":       StatusSucceeded,
+		"retried":  StatusSucceeded,
+		"failed":   StatusFailed,
+		"blocked":  StatusBlocked,
+		"skipped":  StatusSkipped,
+		"timeout":  StatusTimedOut,
+		"rollback": StatusRolledBack,
+	} {
+		res, ok := rep.Result(key)
+		assert.True(t, ok)
+		assert.Equal(t, status, res.Status, key)
+	}
+	res, _ := rep.Result("retried")
+	assert.Equal(t, 3, res.Attempts)
+	res, _ = rep.Result("failed")
+	assert.EqualError(t, res.Err, "failed")
+	assert.Len(t, rep.Results(), 7)
+}
@@ -0,0 +1,162 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region RESULTS
+func TestGoResults(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	square := func(x int) func(context.Context) (int, error) {
+		return func(context.Context) (int, error) {
+			time.Sleep(time.Duration(10-x) * time.Millisecond) // finish out of order
+			return x * x, nil
+		}
+	}
+
+	t.Run("input order", func(t *testing.T) {
+		t.Parallel()
+		var fs []func(context.Context) (int, error)
+		for i := range 10 {
+			fs = append(fs, square(i))
+		}
+		res, err := GoResults(ctx, nil, fs...)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 4, 9, 16, 25, 36, 49, 64, 81}, res)
+
+		res, err = GoResults(ctx, Opts(WithLimit(3), WithPrefix("squares")), fs...)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 4, 9, 16, 25, 36, 49, 64, 81}, res)
+
+		res, err = GoResults[int](ctx, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("partial results", func(t *testing.T) {
+		t.Parallel()
+		boom := errors.New("boom")
+		res, err := GoResults(ctx, Opts(WithLimit(1)),
+			square(1),
+			func(context.Context) (int, error) { return 0, boom },
+			square(3),
+		)
+		assert.ErrorIs(t, err, boom)
+		assert.Equal(t, []int{1, 0, 0}, res)
+
+		var resErr *ResultsError
+		assert.ErrorAs(t, err, &resErr)
+		assert.NoError(t, resErr.Errs[0])
+		assert.ErrorIs(t, resErr.Errs[1], boom)
+		assert.EqualError(t, resErr.Errs[1], "func #1: boom")
+		assert.ErrorIs(t, resErr.Errs[2], ErrNotRun)
+		assert.Equal(t, []int{1, 2}, resErr.Failed())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+		res, err := GoResults(ctx, Opts(),
+			square(2),
+			func(context.Context) (int, error) { time.Sleep(20 * time.Millisecond); panic("oops") },
+		)
+		assert.ErrorIs(t, err, ErrPanic)
+		assert.Equal(t, 4, res[0])
+		var resErr *ResultsError
+		assert.ErrorAs(t, err, &resErr)
+		assert.ErrorIs(t, resErr.Errs[1], ErrPanic)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		res, err := GoResults(ctx, Opts(WithTimeout(50*time.Millisecond), WithPrefix("slow")),
+			square(1),
+			func(context.Context) (int, error) { time.Sleep(200 * time.Millisecond); return 1, nil },
+		)
+		assert.EqualError(t, err, "group slow timeout")
+		assert.Equal(t, []int{1, 0}, res)
+		var resErr *ResultsError
+		assert.ErrorAs(t, err, &resErr)
+		assert.NoError(t, resErr.Errs[0])
+		assert.EqualError(t, resErr.Errs[1], "func #1: group slow timeout")
+		time.Sleep(200 * time.Millisecond) // late results are dropped
+		assert.Equal(t, []int{1, 0}, res)
+	})
+
+	t.Run("interceptors and error collector", func(t *testing.T) {
+		t.Parallel()
+		var pre atomic.Bool
+		errC := make(chan error, 2)
+		res, err := GoResults(ctx, Opts(
+			WithLimit(1),
+			WithPreFunc(func(context.Context) error { pre.Store(true); return nil }),
+			WithAfterFunc(func(_ context.Context, err error) error { return nil }), // swallow errors
+			WithErrorCollector(errC),
+		),
+			func(context.Context) (string, error) { return "a", nil },
+			func(context.Context) (string, error) { return "", errors.New("b failed") },
+		)
+		assert.NoError(t, err)
+		assert.True(t, pre.Load())
+		assert.Equal(t, []string{"a", ""}, res)
+		close(errC)
+		var collected []error
+		for err := range errC {
+			collected = append(collected, err)
+		}
+		assert.Len(t, collected, 1)
+	})
+
+	t.Run("group context", func(t *testing.T) {
+		t.Parallel()
+		started := make(chan struct{}) // the first func fails once the second one started
+		_, err := GoResults(ctx, Opts(),
+			func(context.Context) (int, error) { <-started; return 0, errors.New("boom") },
+			func(ctx context.Context) (int, error) {
+				close(started)
+				select {
+				case <-ctx.Done(): // cancelled by the first error
+					return 0, ctx.Err()
+				case <-time.After(time.Second):
+					return 1, nil
+				}
+			},
+		)
+		var resErr *ResultsError
+		assert.ErrorAs(t, err, &resErr)
+		assert.ErrorIs(t, resErr.Errs[1], context.Canceled)
+	})
+
+	t.Run("join mode attribution", func(t *testing.T) {
+		t.Parallel()
+		errB := errors.New("b failed")
+		_, err := GoResults(ctx, Opts(WithJoinErrors),
+			func(context.Context) (int, error) { panic("oops") },
+			func(context.Context) (int, error) { time.Sleep(20 * time.Millisecond); return 0, errB },
+		)
+		var resErr *ResultsError
+		assert.ErrorAs(t, err, &resErr)
+		assert.ErrorIs(t, resErr.Errs[0], ErrPanic)
+		assert.NotErrorIs(t, resErr.Errs[0], errB) // only its own error
+		assert.EqualError(t, resErr.Errs[1], "func #1: b failed")
+	})
+
+	t.Run("error collector names the func", func(t *testing.T) {
+		t.Parallel()
+		errC := make(chan error, 1)
+		_, _ = GoResults(ctx, Opts(WithErrorCollector(errC)), fetchFailed)
+		assert.Contains(t, (<-errC).Error(), "fetchFailed")
+	})
+}
+
+func fetchFailed(context.Context) (string, error) {
+	return "", errors.New("fetch failed")
+}
@@ -84,3 +84,39 @@ func TestGroupGoStore(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, 4, res)
 }
+
+func TestBuiltinStores(t *testing.T) {
+	t.Parallel()
+
+	for name, newStore := range map[string]func() Storer{
+		"map":     func() Storer { return NewMapStore() },
+		"sharded": func() Storer { return NewShardedStore(4) },
+		"syncMap": func() Storer { return NewSyncMapStore() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			store := newStore()
+			ctx := WithStore(context.Background(), store)
+
+			// concurrent writers with disjoint and overlapping keys
+			var fs []func(context.Context) error
+			for i := range 100 {
+				fs = append(fs, func(ctx context.Context) error {
+					Put(ctx, i, i)
+					Put(ctx, "shared", i)
+					return nil
+				})
+			}
+			assert.NoError(t, NewGroup().AddTasks(fs...).Group.Go(ctx))
+			for i := range 100 {
+				v, ok := Fetch[int](ctx, i)
+				assert.True(t, ok)
+				assert.Equal(t, i, v)
+			}
+			_, ok := Fetch[int](ctx, "shared")
+			assert.True(t, ok)
+			_, ok = store.Load("missing")
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// counting yields 0..n-1 and records how many inputs were pulled
+func counting(n int, pulled *atomic.Int32) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			pulled.Add(1)
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// region STREAM
+func TestForEach(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("bounded parallelism", func(t *testing.T) {
+		t.Parallel()
+		var pulled, running, peak, sum atomic.Int32
+		err := ForEach(ctx, Opts(WithLimit(3)), counting(20, &pulled), func(_ context.Context, x int) error {
+			cur := running.Add(1)
+			for p := peak.Load(); cur > p && !peak.CompareAndSwap(p, cur); p = peak.Load() {
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			sum.Add(int32(x))
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(20), pulled.Load())
+		assert.Equal(t, int32(190), sum.Load())
+		assert.LessOrEqual(t, peak.Load(), int32(3))
+	})
+
+	t.Run("first error cancels", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		boom := errors.New("boom")
+		err := ForEach(ctx, Opts(WithLimit(1)), counting(100, &pulled), func(_ context.Context, x int) error {
+			if x == 2 {
+				return boom
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, boom)
+		assert.Less(t, pulled.Load(), int32(100)) // remaining inputs are never pulled
+	})
+
+	t.Run("join errors", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		err := ForEach(ctx, Opts(WithJoinErrors), counting(6, &pulled), func(_ context.Context, x int) error {
+			if x%3 == 0 {
+				return fmt.Errorf("err %d", x)
+			}
+			return nil
+		})
+		assert.Equal(t, int32(6), pulled.Load())
+		var resErr *ResultsError
+		assert.ErrorAs(t, err, &resErr)
+		assert.Len(t, resErr.Errs, 6)
+		assert.Equal(t, []int{0, 3}, resErr.Failed())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		err := ForEach(ctx, Opts(WithTimeout(30*time.Millisecond), WithPrefix("slow"), WithLimit(2)), counting(100, &pulled),
+			func(ctx context.Context, _ int) error {
+				select {
+				case <-time.After(20 * time.Millisecond):
+				case <-ctx.Done():
+				}
+				return nil
+			})
+		assert.EqualError(t, err, "group slow timeout")
+		assert.Less(t, pulled.Load(), int32(100))
+	})
+
+	t.Run("timeout stops pulling", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		err := ForEach(ctx, Opts(WithTimeout(20*time.Millisecond), WithPrefix("stuck"), WithLimit(1)), counting(100, &pulled),
+			func(context.Context, int) error {
+				time.Sleep(50 * time.Millisecond) // ignores cancellation
+				return nil
+			})
+		assert.EqualError(t, err, "group stuck timeout")
+		n := pulled.Load()
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, n, pulled.Load()) // seq is not pulled after returning
+	})
+
+	t.Run("error collector", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		errC := make(chan error, 10)
+		err := ForEach(ctx, Opts(WithErrorCollector(errC), WithJoinErrors), counting(4, &pulled), func(_ context.Context, x int) error {
+			if x%2 == 1 {
+				return errors.New("odd")
+			}
+			return nil
+		})
+		assert.Error(t, err)
+		assert.Len(t, errC, 2)
+	})
+
+	t.Run("nil opts and empty seq", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, ForEach(ctx, nil, slices.Values([]int{}), func(context.Context, int) error { return nil }))
+	})
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	// later inputs finish first
+	slowSquare := func(_ context.Context, x int) (int, error) {
+		time.Sleep(time.Duration(10-x) * 2 * time.Millisecond)
+		return x * x, nil
+	}
+
+	t.Run("ordered", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		var got []int
+		for v, err := range Map(ctx, Opts(WithLimit(4), WithOrdered), counting(10, &pulled), slowSquare) {
+			assert.NoError(t, err)
+			got = append(got, v)
+		}
+		assert.Equal(t, []int{0, 1, 4, 9, 16, 25, 36, 49, 64, 81}, got)
+	})
+
+	t.Run("unordered", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		var got []int
+		for v, err := range Map(ctx, Opts(WithLimit(10)), counting(10, &pulled), func(_ context.Context, x int) (int, error) {
+			if x != 9 {
+				time.Sleep(50 * time.Millisecond)
+			}
+			return x * x, nil
+		}) {
+			assert.NoError(t, err)
+			got = append(got, v)
+		}
+		assert.Equal(t, 81, got[0]) // fastest first
+		slices.Sort(got)
+		assert.Equal(t, []int{0, 1, 4, 9, 16, 25, 36, 49, 64, 81}, got)
+	})
+
+	t.Run("lazy with backpressure", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		seq := Map(ctx, Opts(WithLimit(2), WithOrdered), counting(1000, &pulled), func(_ context.Context, x int) (int, error) { return x, nil })
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, int32(0), pulled.Load()) // nothing pulled before iterating
+
+		for v := range seq {
+			if v == 0 {
+				time.Sleep(20 * time.Millisecond) // slow consumer
+				assert.LessOrEqual(t, pulled.Load(), int32(6))
+			}
+			if v == 9 {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		assert.Less(t, pulled.Load(), int32(20)) // stopping cancels the remaining inputs
+	})
+
+	t.Run("errors do not stop the stream", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		var errs, oks int
+		for _, err := range Map(ctx, Opts(WithOrdered), counting(6, &pulled), func(_ context.Context, x int) (string, error) {
+			if x%2 == 0 {
+				return "", fmt.Errorf("err %d", x)
+			}
+			return fmt.Sprint(x), nil
+		}) {
+			if err != nil {
+				errs++
+			} else {
+				oks++
+			}
+		}
+		assert.Equal(t, 3, errs)
+		assert.Equal(t, 3, oks)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		var last error
+		n := 0
+		for _, err := range Map(ctx, Opts(WithTimeout(30*time.Millisecond), WithPrefix("slow"), WithLimit(1)), counting(100, &pulled),
+			func(ctx context.Context, x int) (int, error) {
+				time.Sleep(10 * time.Millisecond)
+				return x, nil
+			}) {
+			n++
+			last = err
+		}
+		assert.EqualError(t, last, "group slow timeout")
+		assert.Less(t, n, 100)
+	})
+
+	t.Run("stop stops pulling", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		slow := func(yield func(int) bool) {
+			for x := range counting(1000, &pulled) {
+				time.Sleep(time.Millisecond)
+				if !yield(x) {
+					return
+				}
+			}
+		}
+		for range Map(ctx, Opts(WithLimit(100)), slow, func(_ context.Context, x int) (int, error) { return x, nil }) {
+			break
+		}
+		n := pulled.Load()
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, n, pulled.Load()) // seq is not pulled after returning
+	})
+
+	t.Run("interceptors and panic", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		var pre atomic.Bool
+		var first error
+		var errs []error
+		for _, err := range Map(ctx, Opts(
+			WithLimit(1),
+			WithOrdered,
+			WithPreFunc(func(context.Context) error { pre.Store(true); return nil }),
+			WithAfterFunc(func(_ context.Context, err error) error { first = err; return errors.New("after") }),
+		), counting(3, &pulled), func(_ context.Context, x int) (int, error) {
+			if x == 1 {
+				panic("oops")
+			}
+			return x, nil
+		}) {
+			errs = append(errs, err)
+		}
+		assert.True(t, pre.Load())
+		assert.ErrorIs(t, first, ErrPanic)
+		assert.Len(t, errs, 4) // 3 results and the after error
+		assert.ErrorIs(t, errs[1], ErrPanic)
+		assert.EqualError(t, errs[3], "after")
+	})
+
+	t.Run("repanic after an error", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		assert.Panics(t, func() {
+			for range Map(ctx, Opts(WithOrdered, WithLimit(1), WithPanicPolicy(PanicRepanic)), counting(3, &pulled),
+				func(_ context.Context, x int) (int, error) {
+					if x == 1 {
+						panic("oops")
+					}
+					return x, errors.New("failed")
+				}) {
+			}
+		})
+	})
+
+	t.Run("pre error", func(t *testing.T) {
+		t.Parallel()
+		var pulled atomic.Int32
+		var errs []error
+		for _, err := range Map(ctx, Opts(WithPreFunc(func(context.Context) error { return errors.New("pre") })), counting(3, &pulled),
+			func(_ context.Context, x int) (int, error) { return x, nil }) {
+			errs = append(errs, err)
+		}
+		assert.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "pre")
+		assert.Equal(t, int32(0), pulled.Load())
+	})
+}
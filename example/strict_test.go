@@ -0,0 +1,103 @@
+package group
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region STRICT STORE
+
+func TestGroupStrictStore(t *testing.T) {
+	t.Parallel()
+
+	var produce = func(v int) func(context.Context) (any, error) {
+		return func(context.Context) (any, error) { return v, nil }
+	}
+	var read = func(key any, got *int) func(context.Context) error {
+		return func(ctx context.Context) error {
+			*got, _ = Fetch[int](ctx, key)
+			return nil
+		}
+	}
+
+	t.Run("upstream reads", func(t *testing.T) {
+		t.Parallel()
+		var got, gotInput, gotPut int
+		ctx := WithStore(context.Background(), NewMapStore())
+		Put(ctx, "input", 7) // written outside the run
+		err := NewGroup(WithStrictStore(StrictError)).
+			AddAutoTask(produce(1)).Key("a").
+			AddTask(func(ctx context.Context) error { Put(ctx, "extra", 2); return nil }).Key("b").Dep("a").
+			AddTask(func(ctx context.Context) error {
+				_ = read("a", &got)(ctx) // transitive upstream
+				_ = read("input", &gotInput)(ctx)
+				return read("extra", &gotPut)(ctx)
+			}).Key("c").Dep("b").
+			Go(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got)
+		assert.Equal(t, 7, gotInput)
+		assert.Equal(t, 2, gotPut)
+	})
+
+	t.Run("undeclared node key", func(t *testing.T) {
+		t.Parallel()
+		var got int
+		ctx := WithStore(context.Background(), NewMapStore())
+		err := NewGroup(WithStrictStore(StrictError)).
+			AddAutoTask(produce(1)).Key("a").
+			AddTask(read("a", &got)).Key("b"). // sibling, no dependency
+			Go(ctx)
+		assert.ErrorIs(t, err, ErrUndeclaredRead)
+		assert.ErrorContains(t, err, `node "b" reads "a" of non-upstream node`)
+		assert.Zero(t, got)
+	})
+
+	t.Run("undeclared put key", func(t *testing.T) {
+		t.Parallel()
+		var got int
+		ctx := WithStore(context.Background(), NewMapStore())
+		err := NewGroup(WithStrictStore(StrictError)).
+			AddTask(func(ctx context.Context) error { Put(ctx, "extra", 2); return nil }).Key("a").
+			AddTask(func(ctx context.Context) error {
+				time.Sleep(50 * time.Millisecond) // after a has written
+				return read("extra", &got)(ctx)
+			}).Key("b").
+			Go(ctx)
+		assert.ErrorIs(t, err, ErrUndeclaredRead)
+		assert.ErrorContains(t, err, `node "b" reads "extra" written by non-upstream node "a"`)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+		var got int
+		ctx := WithStore(context.Background(), NewMapStore())
+		assert.Panics(t, func() {
+			_ = NewGroup(WithStrictStore(StrictPanic), WithPanicPolicy(PanicRepanic)).
+				AddAutoTask(produce(1)).Key("a").
+				AddTask(read("a", &got)).Key("b").
+				Go(ctx)
+		})
+		err := NewGroup(WithStrictStore(StrictPanic)).
+			AddAutoTask(produce(1)).Key("a").
+			AddTask(read("a", &got)).Key("b").
+			Go(ctx)
+		assert.ErrorIs(t, err, ErrPanic)
+		assert.ErrorIs(t, err, ErrUndeclaredRead)
+	})
+
+	t.Run("off", func(t *testing.T) {
+		t.Parallel()
+		ctx := WithStore(context.Background(), NewMapStore())
+		var got int
+		assert.NoError(t, NewGroup().
+			AddAutoTask(produce(1)).Key("a").
+			AddTask(read("a", &got)).Key("b").
+			Go(ctx))
+	})
+}
@@ -0,0 +1,103 @@
+package group
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+type chromeEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat"`
+	Ph   string         `json:"ph"`
+	Ts   float64        `json:"ts"`
+	Dur  float64        `json:"dur"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args"`
+}
+
+func TestGroupGoTrace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nodes, retries, timeouts and flows", func(t *testing.T) {
+		t.Parallel()
+		tr := NewTrace()
+		ctx := WithTrace(context.Background(), tr)
+
+		var attempts int
+		err := NewGroup(WithPrefix("traced")).
+			AddRunner(func() error { time.Sleep(10 * time.Millisecond); return nil }).Key("a").
+			AddRunner(func() error {
+				time.Sleep(10 * time.Millisecond)
+				if attempts++; attempts < 2 {
+					return errors.New("flaky")
+				}
+				return nil
+			}).Key("b").Dep("a").WithRetry(1).
+			AddRunner(func() error { time.Sleep(50 * time.Millisecond); return nil }).Key("c").Dep("a").WithTimeout(10 * time.Millisecond).
+			Go(ctx)
+		assert.NotNil(t, err)
+
+		var buf bytes.Buffer
+		_, err = tr.WriteTo(&buf)
+		assert.Nil(t, err)
+
+		var out struct {
+			TraceEvents []chromeEvent `json:"traceEvents"`
+		}
+		assert.Nil(t, json.Unmarshal(buf.Bytes(), &out))
+
+		spans, phases := make(map[string]chromeEvent), make(map[string]int)
+		for _, e := range out.TraceEvents {
+			phases[e.Ph]++
+			if e.Ph == "X" {
+				spans[e.Name] = e
+			}
+		}
+		assert.Len(t, spans, 3)
+		assert.Equal(t, 4, phases["s"]+phases["f"]) // a -> b, a -> c
+		assert.Equal(t, 2, phases["i"])             // retry #1 & timeout
+		assert.GreaterOrEqual(t, spans["b"].Ts, spans["a"].Ts+spans["a"].Dur)
+		assert.Contains(t, spans["c"].Args["error"], "timeout")
+		assert.NotEqual(t, spans["b"].Tid, spans["c"].Tid) // b and c run in parallel
+	})
+
+	t.Run("waits on concurrency limit", func(t *testing.T) {
+		t.Parallel()
+		tr := NewTrace()
+		ctx := WithTrace(context.Background(), tr)
+
+		sleep := func() error { time.Sleep(10 * time.Millisecond); return nil }
+		err := NewGroup(WithLimit(1)).
+			AddRunners(sleep, sleep, sleep).Keys("a", "b", "c").
+			Go(ctx)
+		assert.Nil(t, err)
+
+		var buf bytes.Buffer
+		_, err = tr.WriteTo(&buf)
+		assert.Nil(t, err)
+
+		var out struct {
+			TraceEvents []chromeEvent `json:"traceEvents"`
+		}
+		assert.Nil(t, json.Unmarshal(buf.Bytes(), &out))
+		var waits, tids = 0, make(map[int]struct{})
+		for _, e := range out.TraceEvents {
+			if e.Ph == "b" && e.Cat == "wait" {
+				waits++
+			}
+			if e.Ph == "X" {
+				tids[e.Tid] = struct{}{}
+			}
+		}
+		assert.GreaterOrEqual(t, waits, 2)
+		assert.Len(t, tids, 1) // single worker slot
+	})
+}
@@ -0,0 +1,118 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+type order struct {
+	ID       string
+	Items    []string
+	Charged  bool
+	Refunded bool
+}
+
+// region TYPED
+func TestTypedGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("shared state", func(t *testing.T) {
+		t.Parallel()
+		var pre, after atomic.Int32
+		g := NewGroupOf[*order]().
+			AddSharedRunner(func(o *order) error {
+				o.Items = append(o.Items, "book")
+				return nil
+			}).Key("items").
+			AddSharedTask(func(ctx context.Context, o *order) error {
+				o.Charged = len(o.Items) > 0
+				return nil
+			}).Key("charge").Dep("items").
+			WithPreFunc(func(ctx context.Context, o *order) error { pre.Add(1); return nil }).
+			WithAfterFunc(func(ctx context.Context, o *order, err error) error { after.Add(1); return err }).
+			AddAutoSharedTask(func(ctx context.Context, o *order) (any, error) { return "receipt-" + o.ID, nil }).Key("receipt").Dep("charge").
+			AddSharedRunner(func(o *order) error { panic("skipped") }).Key("gift").
+			WithCondition(func(ctx context.Context, o *order) bool { return len(o.Items) > 1 }).Dep("items").
+			Group
+
+		o := &order{ID: "42"}
+		store := NewMapStore()
+		assert.NoError(t, g.Go(WithStore(context.Background(), store), o))
+		assert.Equal(t, []string{"book"}, o.Items)
+		assert.True(t, o.Charged)
+		assert.Equal(t, int32(1), pre.Load())
+		assert.Equal(t, int32(1), after.Load())
+		v, _ := store.Load("receipt")
+		assert.Equal(t, "receipt-42", v)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroupOf[*order]().
+			AddSharedRunner(func(o *order) error { o.Charged = true; return nil }).Key("charge").
+			WithRollback(func(ctx context.Context, o *order, err error) error { o.Refunded = true; return nil }).
+			AddRunner(func() error { return errors.New("ship failed") }).Key("ship").Dep("charge").FastFail().
+			Group
+
+		o := &order{}
+		assert.ErrorContains(t, g.Go(context.Background(), o), "ship failed")
+		assert.True(t, o.Refunded)
+	})
+
+	t.Run("value state and node lookup", func(t *testing.T) {
+		t.Parallel()
+		type config struct{ Retries int }
+		var attempts atomic.Int32
+		g := NewGroupOf[config](WithPrefix("typed"))
+		g.AddSharedRunner(func(c config) error {
+			if attempts.Add(1) <= int32(c.Retries) {
+				return errors.New("flaky")
+			}
+			return nil
+		}).Key("flaky")
+		g.Node("flaky").WithRetry(2)
+		assert.Nil(t, g.Node("missing"))
+		assert.NoError(t, g.Go(context.Background(), config{Retries: 2}))
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("nil state", func(t *testing.T) {
+		t.Parallel()
+		var got *order = &order{}
+		g := NewGroupOf[*order]().AddSharedRunner(func(o *order) error { got = o; return nil }).Group
+		assert.NoError(t, g.Go(context.Background(), nil))
+		assert.Nil(t, got)
+	})
+
+	t.Run("batch and serial adders", func(t *testing.T) {
+		t.Parallel()
+		var before, rolledBack atomic.Int32
+		g := NewGroupOf[*order]()
+		g.AddSharedRunners(
+			func(o *order) error { o.Items = append(o.Items, "book"); return nil },
+			func(o *order) error { return nil },
+		).Keys("items", "noop").
+			WithPreFunc(func(ctx context.Context, o *order) error { before.Add(1); return nil }).
+			WithRollback(func(ctx context.Context, o *order, err error) error { rolledBack.Add(1); return nil })
+		g.AddSerialSharedTasks(
+			func(ctx context.Context, o *order) error { o.Charged = true; return nil },
+			func(ctx context.Context, o *order) error { return errors.New("ship failed") },
+		).Key("checkout").Dep("items", "noop").FastFail()
+
+		o := &order{}
+		assert.ErrorContains(t, g.Go(context.Background(), o), "ship failed")
+		assert.Equal(t, []string{"book"}, o.Items)
+		assert.True(t, o.Charged)
+		assert.Equal(t, int32(2), before.Load())
+		assert.Equal(t, int32(2), rolledBack.Load())
+		levels, err := g.Levels()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]any{{"items", "noop"}, {"checkout"}}, levels)
+	})
+}
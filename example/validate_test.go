@@ -0,0 +1,142 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oatcatx/group"
+)
+
+// region VALIDATE
+
+func TestGroupValidate(t *testing.T) {
+	t.Parallel()
+
+	var f = func() error { return nil }
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup().
+			AddRunner(f).Key("a").
+			AddRunner(f).Key("b").Dep("a").
+			AddRunner(f).Key("c").Dep("b").Group
+		assert.NoError(t, g.Validate())
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup().
+			AddRunner(f).Key("a").
+			AddRunner(f).Key("b").Dep("a").
+			AddRunner(f).Key("c").
+			AddRunner(f).Key("d").Dep("c").
+			AddRunner(f).Key("e").Dep("b").
+			AddRunner(f).Dep("e").Group
+		g.Node("a").Dep("b")
+		g.Node("c").Dep("d")
+
+		err := g.Validate()
+		assert.ErrorIs(t, err, ErrCycle)
+		var verr *ValidationError
+		assert.True(t, errors.As(err, &verr))
+		cycles := verr.Filter(IssueCycle)
+		assert.Len(t, cycles, 2)
+		assert.ElementsMatch(t, []any{"a", "b"}, cycles[0].Keys)
+		assert.ElementsMatch(t, []any{"c", "d"}, cycles[1].Keys)
+		// downstream nodes including anonymous ones
+		unreachable := verr.Filter(IssueUnreachable)
+		assert.Len(t, unreachable, 2)
+		assert.Equal(t, []any{"e"}, unreachable[0].Keys)
+		assert.Equal(t, []any{"node_5"}, unreachable[1].Keys)
+	})
+
+	t.Run("self cycle", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup().AddRunner(f).Key("a").Dep("a").Group
+		var verr *ValidationError
+		assert.True(t, errors.As(g.Validate(), &verr))
+		assert.Equal(t, []Issue{{Kind: IssueCycle, Keys: []any{"a"}, Msg: `"a" -> "a"`}}, verr.Issues)
+	})
+
+	t.Run("fail configurations", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup().
+			AddRunner(f).Key("sf").SilentFail().
+			AddRunner(f).Key("ff").FastFail().
+			AddRunner(f).Key("a").Dep("sf").
+			AddRunner(f).Key("b").WeakDep("ff").Group
+		assert.NoError(t, g.Validate()) // warnings only
+		issues := g.Issues()
+		assert.Len(t, issues, 2)
+		assert.Equal(t, Issue{Kind: IssueSilentBlock, Keys: []any{"sf", "a"}, Msg: issues[0].Msg}, issues[0])
+		assert.Equal(t, Issue{Kind: IssueFastFailWeak, Keys: []any{"ff", "b"}, Msg: issues[1].Msg}, issues[1])
+		assert.Equal(t, SeverityWarning, issues[0].Severity())
+		assert.Equal(t, SeverityWarning, issues[1].Severity())
+		assert.NoError(t, g.Go(context.Background()))
+
+		g.AddRunner(f).Key("c").Dep("c") // an error reports the warnings too
+		var verr *ValidationError
+		assert.True(t, errors.As(g.Validate(), &verr))
+		assert.ErrorIs(t, verr, ErrCycle)
+		assert.Contains(t, verr.Error(), "- warning: silent block:")
+		assert.Contains(t, verr.Error(), "- warning: fast-fail weak dependency:")
+	})
+
+	t.Run("weak chain", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup().
+			AddRunner(f).Key("a").
+			AddRunner(f).Key("b").WeakDep("a").
+			AddRunner(f).Key("c").WeakDep("b").
+			AddRunner(f).Key("d").WeakDep("c").Dep("a").Group
+		assert.NoError(t, g.Validate()) // warnings only
+		assert.Equal(t, []Issue{{Kind: IssueWeakChain, Keys: []any{"a", "b", "c"}, Msg: `"a" -> "b" -> "c"`}}, g.Issues())
+	})
+
+	t.Run("wide weak chains", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup().AddRunner(f).Key(0).Group
+		for i := 1; i <= 40; i++ { // 2^40 weak paths
+			g.AddRunner(f).Key(fmt.Sprint(i, "a")).WeakDep(prevKeys(i)...)
+			g.AddRunner(f).Key(fmt.Sprint(i, "b")).WeakDep(prevKeys(i)...)
+		}
+		issues := g.Issues()
+		assert.Len(t, issues, 2) // one per tail
+		assert.Len(t, issues[0].Keys, 41)
+	})
+
+	t.Run("redundant edge", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup().
+			AddRunner(f).Key("a").
+			AddRunner(f).Key("b").Dep("a").
+			AddRunner(f).Key("c").Dep("b").
+			AddRunner(f).Key("d").Dep("a", "c").Group
+		assert.NoError(t, g.Validate()) // warnings only
+		assert.Equal(t, []Issue{{Kind: IssueRedundantEdge, Keys: []any{"a", "d"}, Msg: `"d" -> "a" is implied by "c"`}}, g.Issues())
+	})
+
+	t.Run("option conflict", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup(WithTimeout(time.Second)).
+			AddRunner(f).Key("a").WithTimeout(time.Second).
+			AddRunner(f).Key("b").WithTimeout(time.Millisecond).Group
+		var verr *ValidationError
+		assert.True(t, errors.As(g.Validate(), &verr))
+		assert.Equal(t, []any{"a"}, verr.Filter(IssueOptionConflict)[0].Keys)
+		assert.Len(t, verr.Issues, 1)
+	})
+}
+
+// prevKeys returns the keys of layer i-1 of the wide weak chains
+func prevKeys(i int) []any {
+	if i == 1 {
+		return []any{0}
+	}
+	return []any{fmt.Sprint(i-1, "a"), fmt.Sprint(i-1, "b")}
+}
@@ -1,7 +1,9 @@
 package group
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -44,3 +46,69 @@ func TestGroupGoVerify(t *testing.T) {
 		AddRunner(c).Key("c").Dep("b").Group
 	g.Node("a").Dep("c").Verify(true)
 }
+
+func TestGroupGoForwardDep(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolved", func(t *testing.T) {
+		t.Parallel()
+		var order []string
+		var f = func(key string) func() error {
+			return func() error { order = append(order, key); return nil }
+		}
+		g := NewGroup().
+			AddRunner(f("c")).Key("c").Dep("b").WeakDep("a").
+			AddRunner(f("b")).Key("b").Dep("a").
+			AddRunner(f("a")).Key("a").Group
+		assert.NoError(t, g.Go(context.Background()))
+		assert.Equal(t, []string{"a", "b", "c"}, order)
+	})
+
+	t.Run("interface nodes", func(t *testing.T) {
+		t.Parallel()
+		g := NewGroup()
+		g.AddNode(nodeB{}) // depends on nodeA added later
+		g.AddNode(nodeA{})
+		assert.NoError(t, g.Go(context.Background()))
+	})
+
+	t.Run("concurrent first runs", func(t *testing.T) {
+		t.Parallel()
+		var f = func() error { return nil }
+		g := NewGroup().
+			AddRunner(f).Key("c").Dep("b").WeakDep("a").
+			AddRunner(f).Key("b").Dep("a").
+			AddRunner(f).Key("a").Group
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Go(func() { assert.NoError(t, g.Go(context.Background())) })
+			wg.Go(func() { assert.NoError(t, g.Validate()) })
+			wg.Go(func() { assert.NoError(t, g.Clone().Go(context.Background())) })
+		}
+		wg.Wait()
+	})
+
+	t.Run("unresolved", func(t *testing.T) {
+		t.Parallel()
+		var f = func() error { return nil }
+		g := NewGroup().
+			AddRunner(f).Key("a").Dep("x").
+			AddRunner(f).Key("b").WeakDep("y").Group
+		err := g.Go(context.Background())
+		assert.ErrorIs(t, err, ErrMissingDependency)
+		assert.ErrorContains(t, err, `missing dependency "a" -> "x"`)
+		assert.ErrorContains(t, err, `missing dependency "b" -> "y"`)
+		assert.ErrorIs(t, g.Validate(), ErrMissingDependency)
+
+		// resolved once the upstreams are added
+		g.AddRunner(f).Key("x").AddRunner(f).Key("y")
+		assert.NoError(t, g.Go(context.Background()))
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		t.Parallel()
+		assert.PanicsWithValue(t, fmt.Sprintf("duplicate dependency %q -> %q", "a", "x"), func() {
+			NewGroup().AddRunner(func() error { return nil }).Key("a").Dep("x").Dep("x")
+		})
+	})
+}
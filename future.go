@@ -0,0 +1,156 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrNotResolved = errors.New("future not resolved")
+
+// Future is the typed result of a node, retrievable during or after Group.Go without a storer-context
+/*
+ * the future itself is the node key (unless keyed otherwise) and can be passed to Dep / WeakDep of other nodes
+ * it holds the result of the latest run, use a storer-context for concurrent runs of the same group
+ * it is resolved by runs of its own group only, not by clones or groups including it
+ * the value is also stored into the storer-context if any (as auto nodes)
+ */
+type Future[T any] struct {
+	n *node
+
+	mu    sync.Mutex
+	run   bool          // a run has started
+	done  chan struct{} // closed when the node finishes or the run ends
+	value T
+	err   error
+}
+
+// futureHook resolves the future of a node during group runs
+type futureHook interface {
+	reset()
+	finish(v any, ok bool, err error)
+	close()
+}
+
+// resultSlot holds the value stored by a node execution (futures, caches and dedups)
+type resultSlot struct {
+	mu sync.Mutex
+	v  any
+	ok bool
+}
+
+type resultSlotKey struct{}
+
+// resultSlotOf returns the result slot of the node execution, nil if absent
+func resultSlotOf(ctx context.Context) *resultSlot {
+	slot, _ := ctx.Value(resultSlotKey{}).(*resultSlot)
+	return slot
+}
+
+func (s *resultSlot) set(v any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.v, s.ok = v, true
+}
+
+func (s *resultSlot) get() (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.v, s.ok
+}
+
+// AddFuture adds a node returning a typed value, see Future
+func AddFuture[T any](g *Group, f func(ctx context.Context, shared any) (T, error)) *Future[T] {
+	fut := &Future[T]{done: make(chan struct{})}
+	fut.n = g.addNode(func(ctx context.Context, shared any) error {
+		v, err := f(ctx, shared)
+		if err != nil {
+			return err
+		}
+		if store, _ := ctx.Value(storeKey{}).(storeFunc); store != nil {
+			store(v)
+		}
+		return nil
+	}).Key(fut)
+	fut.n.future = fut
+	return fut
+}
+
+func AddFutureRunner[T any](g *Group, runner func() (T, error)) *Future[T] {
+	return AddFuture(g, func(context.Context, any) (T, error) { return runner() })
+}
+
+func AddFutureTask[T any](g *Group, task func(context.Context) (T, error)) *Future[T] {
+	return AddFuture(g, func(ctx context.Context, _ any) (T, error) { return task(ctx) })
+}
+
+// Node returns the node of the future for configuration
+func (f *Future[T]) Node() *node {
+	return f.n
+}
+
+// Get blocks until the node finishes in the current (or returns the result of the latest) run
+/*
+ * returns ErrNotResolved if no run has started, or the node did not run (skipped, blocked or cancelled)
+ */
+func (f *Future[T]) Get() (T, error) {
+	f.mu.Lock()
+	if !f.run {
+		f.mu.Unlock()
+		var zero T
+		return zero, ErrNotResolved
+	}
+	done := f.done
+	f.mu.Unlock()
+	<-done
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.value, f.err
+}
+
+// Done returns a channel closed when the future is resolved in the current run
+func (f *Future[T]) Done() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.done
+}
+
+func (f *Future[T]) String() string {
+	return fmt.Sprintf("future_%d", f.n.idx)
+}
+
+func (f *Future[T]) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var zero T
+	select {
+	case <-f.done:
+		f.done = make(chan struct{})
+	default:
+	}
+	f.run, f.value, f.err = true, zero, nil
+}
+
+func (f *Future[T]) finish(v any, ok bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	select {
+	case <-f.done:
+		return // already resolved
+	default:
+	}
+	switch {
+	case err != nil:
+		f.err = err
+	case ok:
+		f.value, _ = v.(T) // nil for nil-able T restored from a cache
+	default:
+		f.err = ErrNotResolved // skipped
+	}
+	close(f.done)
+}
+
+func (f *Future[T]) close() {
+	f.finish(nil, false, ErrNotResolved)
+}
@@ -10,7 +10,41 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-func Go(ctx context.Context, opts *Options, fs ...func() error) (err error) {
+func Go(ctx context.Context, opts *Options, fs ...func() error) error {
+	return goFuncs(ctx, opts, liftFuncs(fs))
+}
+
+// GoCtx executes fs as Go does, passing them the group context
+/*
+ * the group context is cancelled by the first error (or the error threshold), or on group timeout
+ */
+func GoCtx(ctx context.Context, opts *Options, fs ...func(context.Context) error) error {
+	return goFuncs(ctx, opts, ctxFuncs(fs))
+}
+
+// groupFunc is a func of Go / TryGo, fn is the user func named in labels and logs
+type groupFunc struct {
+	f  func(context.Context) error
+	fn any
+}
+
+func liftFuncs(fs []func() error) []groupFunc {
+	gfs := make([]groupFunc, len(fs))
+	for i, f := range fs {
+		gfs[i] = groupFunc{f: func(context.Context) error { return f() }, fn: f}
+	}
+	return gfs
+}
+
+func ctxFuncs(fs []func(context.Context) error) []groupFunc {
+	gfs := make([]groupFunc, len(fs))
+	for i, f := range fs {
+		gfs[i] = groupFunc{f: f, fn: f}
+	}
+	return gfs
+}
+
+func goFuncs(ctx context.Context, opts *Options, fs []groupFunc) (err error) {
 	if len(fs) == 0 {
 		return nil
 	}
@@ -19,7 +53,7 @@ func Go(ctx context.Context, opts *Options, fs ...func() error) (err error) {
 	if opts == nil {
 		g, gtx := errgroup.WithContext(ctx)
 		g.SetLimit(len(fs)) // limit defaults to number of funcs
-		exec(gtx, g, nil, fs...)
+		exec(gtx, g, nil, nil, fs...)
 		return g.Wait()
 	}
 
@@ -38,7 +72,8 @@ func Go(ctx context.Context, opts *Options, fs ...func() error) (err error) {
 		limit = opts.limit
 	}
 
-	g, ctx := errgroup.WithContext(ctx)
+	g, ctx, j := opts.errgroup(ctx, len(fs))
+	defer j.close()
 	g.SetLimit(limit)
 
 	// group timeout
@@ -50,17 +85,26 @@ func Go(ctx context.Context, opts *Options, fs ...func() error) (err error) {
 
 	// group pre-execution interceptor
 	if opts.pre != nil {
-		if err := opts.pre(ctx); err != nil {
+		if err := opts.safeRun(ctx, func() error { return opts.pre(ctx) }); err != nil {
+			opts.repanic(err)
 			return err
 		}
 	}
-	exec(ctx, g, opts, fs...)
-	// group post-execution interceptor
-	if opts.after != nil {
-		defer func() {
-			err = opts.after(ctx, err)
-		}()
-	}
+	exec(ctx, g, opts, j, fs...)
+	defer func() {
+		p, _ := opts.haltOnPanic(err)
+		// group post-execution interceptor
+		if opts.after != nil {
+			e := err
+			if err = opts.safeRun(ctx, func() error { return opts.after(ctx, e) }); p == nil {
+				p, _ = opts.haltOnPanic(err)
+			}
+		}
+		// re-panic after cleanup
+		if p != nil && opts.panicPolicy == PanicRepanic {
+			panic(p)
+		}
+	}()
 
 	// outer timeout control
 	if opts.timeout > 0 {
@@ -71,28 +115,26 @@ func Go(ctx context.Context, opts *Options, fs ...func() error) (err error) {
 		select {
 		case <-ctx.Done():
 			if errors.Is(ctx.Err(), context.DeadlineExceeded) { // actual timeout
-				if opts.log {
-					slog.InfoContext(ctx, fmt.Sprintf("[Group::Go] group %s timeout", opts.prefix), slog.Duration("after", opts.timeout))
-				}
-				return fmt.Errorf("group %s timeout", opts.prefix)
+				return opts.timeoutErr(ctx, "Go")
 			}
-			return <-done
+			return j.wait(ctx, <-done)
 		case err = <-done:
-			return
+			return j.wait(ctx, err)
 		}
 	}
-	return g.Wait()
+	return j.wait(ctx, g.Wait())
 }
 
-func GoCtx(ctx context.Context, opts *Options, fs ...func(context.Context) error) error {
-	fcs := make([]func() error, 0, len(fs))
-	for _, f := range fs {
-		fcs = append(fcs, func() error { return f(ctx) })
-	}
-	return Go(ctx, opts, fcs...)
+func TryGo(ctx context.Context, opts *Options, fs ...func() error) (bool, error) {
+	return tryGoFuncs(ctx, opts, liftFuncs(fs))
+}
+
+// TryGoCtx executes fs as TryGo does, passing them the group context
+func TryGoCtx(ctx context.Context, opts *Options, fs ...func(context.Context) error) (bool, error) {
+	return tryGoFuncs(ctx, opts, ctxFuncs(fs))
 }
 
-func TryGo(ctx context.Context, opts *Options, fs ...func() error) (ok bool, err error) {
+func tryGoFuncs(ctx context.Context, opts *Options, fs []groupFunc) (ok bool, err error) {
 	if len(fs) == 0 {
 		return true, nil
 	}
@@ -102,7 +144,7 @@ func TryGo(ctx context.Context, opts *Options, fs ...func() error) (ok bool, err
 		g, ctx := errgroup.WithContext(ctx)
 		// limit defaults to number of funcs
 		g.SetLimit(len(fs))
-		return tryExec(ctx, g, nil, fs...), g.Wait()
+		return tryExec(ctx, g, nil, nil, fs...), g.Wait()
 	}
 
 	if opts.limit < len(fs) {
@@ -117,7 +159,8 @@ func TryGo(ctx context.Context, opts *Options, fs ...func() error) (ok bool, err
 		}(time.Now())
 	}
 
-	g, gtx := errgroup.WithContext(ctx)
+	g, gtx, j := opts.errgroup(ctx, len(fs))
+	defer j.close()
 	limit := len(fs) // limit defaults to number of funcs
 	if opts.limit > 0 {
 		limit = opts.limit
@@ -132,17 +175,26 @@ func TryGo(ctx context.Context, opts *Options, fs ...func() error) (ok bool, err
 
 	// group pre-execution interceptor
 	if opts.pre != nil {
-		if err = opts.pre(ctx); err != nil {
+		if err = opts.safeRun(ctx, func() error { return opts.pre(ctx) }); err != nil {
+			opts.repanic(err)
 			return
 		}
 	}
-	ok = tryExec(gtx, g, opts, fs...)
-	// group post-execution interceptor
-	if opts.after != nil {
-		defer func() {
-			err = opts.after(ctx, err)
-		}()
-	}
+	ok = tryExec(gtx, g, opts, j, fs...)
+	defer func() {
+		p, _ := opts.haltOnPanic(err)
+		// group post-execution interceptor
+		if opts.after != nil {
+			e := err
+			if err = opts.safeRun(ctx, func() error { return opts.after(ctx, e) }); p == nil {
+				p, _ = opts.haltOnPanic(err)
+			}
+		}
+		// re-panic after cleanup
+		if p != nil && opts.panicPolicy == PanicRepanic {
+			panic(p)
+		}
+	}()
 
 	// outer timeout control
 	if opts.timeout > 0 {
@@ -153,75 +205,69 @@ func TryGo(ctx context.Context, opts *Options, fs ...func() error) (ok bool, err
 		select {
 		case <-ctx.Done():
 			if errors.Is(ctx.Err(), context.DeadlineExceeded) { // actual timeout
-				if opts.log {
-					slog.InfoContext(ctx, fmt.Sprintf("[Group::TryGo] group %s timeout", opts.prefix), slog.Duration("after", opts.timeout))
-				}
-				return ok, fmt.Errorf("group %s timeout", opts.prefix)
+				return ok, opts.timeoutErr(ctx, "TryGo")
 			}
-			return ok, <-done
+			return ok, j.wait(gtx, <-done)
 		case err = <-done:
-			return
+			return ok, j.wait(gtx, err)
 		}
 	}
-	return ok, g.Wait()
+	return ok, j.wait(gtx, g.Wait())
 }
 
-func TryGoCtx(ctx context.Context, opts *Options, fs ...func(context.Context) error) (bool, error) {
-	fcs := make([]func() error, 0, len(fs))
-	for _, f := range fs {
-		fcs = append(fcs, func() error { return f(ctx) })
+func exec(ctx context.Context, g *errgroup.Group, opts *Options, j *joinedErrs, fs ...groupFunc) {
+	for i, f := range fs {
+		g.Go(execFunc(ctx, "[Go -> exec]", opts, j, i, f.f, f.fn))
 	}
-	return TryGo(ctx, opts, fcs...)
 }
 
-func exec(ctx context.Context, g *errgroup.Group, opts *Options, fs ...func() error) {
-	for _, f := range fs {
-		g.Go(func() (err error) {
-			// ctx check before exec
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
-
-			// no opts short circuit
-			if opts == nil || !opts.log && opts.ErrC == nil {
-				return SafeRun(ctx, f)
-			}
-
-			if opts.log || opts.ErrC != nil {
-				defer func(start time.Time) {
-					funcMonitor(ctx, "[Go -> exec]", opts.prefix, funcName(f), start, opts.log, opts.ErrC, err)
-				}(time.Now())
-			}
-			return SafeRun(ctx, f)
-		})
+func tryExec(ctx context.Context, g *errgroup.Group, opts *Options, j *joinedErrs, fs ...groupFunc) bool {
+	ok := true
+	for i, f := range fs {
+		ok = ok && g.TryGo(execFunc(ctx, "[TryGo -> exec]", opts, j, i, f.f, f.fn))
 	}
+	return ok
 }
 
-func tryExec(ctx context.Context, g *errgroup.Group, opts *Options, fs ...func() error) bool {
-	ok := true
-	for _, f := range fs {
-		ok = ok && g.TryGo(func() (err error) {
-			// ctx check before exec
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
+// execFunc wraps the i-th func f run with the group context ctx, fn is the func named in labels and logs
+func execFunc(ctx context.Context, method string, opts *Options, j *joinedErrs, i int, f func(context.Context) error, fn any) func() error {
+	return func() (err error) {
+		// ctx check before exec
+		select {
+		case <-ctx.Done():
+			j.skip(i)
+			return ctx.Err()
+		default:
+		}
+		defer func() { j.record(i, err) }()
 
-			// no opts short circuit
-			if opts == nil || !opts.log && opts.ErrC == nil {
-				return SafeRun(ctx, f)
+		run := func() error { return f(ctx) }
+		// no opts short circuit
+		if opts == nil {
+			return SafeRun(ctx, run)
+		}
+		if opts.profiling(ctx) {
+			run = func() error {
+				return doLabeled(ctx, f, LabelGroup, opts.prefix, LabelFunc, funcName(fn))
 			}
+		}
+		if !opts.log && opts.ErrC == nil {
+			return opts.safeRun(ctx, run)
+		}
 
-			if opts.log || opts.ErrC != nil {
-				defer func(start time.Time) {
-					funcMonitor(ctx, "[TryGo -> exec]", opts.prefix, funcName(f), start, opts.log, opts.ErrC, err)
-				}(time.Now())
-			}
-			return SafeRun(ctx, f)
-		})
+		if opts.log || opts.ErrC != nil {
+			defer func(start time.Time) {
+				funcMonitor(ctx, method, opts.prefix, funcName(fn), start, opts.log, opts.ErrC, err)
+			}(time.Now())
+		}
+		return opts.safeRun(ctx, run)
 	}
-	return ok
+}
+
+// timeoutErr logs and returns the group timeout error
+func (o *Options) timeoutErr(ctx context.Context, method string) error {
+	if o.log {
+		slog.InfoContext(ctx, fmt.Sprintf("[Group::%s] group %s timeout", method, o.prefix), slog.Duration("after", o.timeout))
+	}
+	return fmt.Errorf("group %s timeout", o.prefix)
 }
@@ -2,14 +2,17 @@ package group
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"image"
 	"io"
+	"maps"
 	"net/url"
 	"os"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/goccy/go-graphviz"
 	"github.com/goccy/go-graphviz/cgraph"
@@ -27,6 +30,25 @@ type GraphOptions struct {
 	WeakEdgeStyle   cgraph.EdgeStyle // style for weak dependency edges (dashed, dotted)
 	ShowGroupInfo   bool             // show group options in title
 	ShowNodeSpec    bool             // show node spec details
+
+	Report        *Report               // overlay run results (status, duration, attempts)
+	StatusColors  map[NodeStatus]string // node colors by run status
+	FailEdgeColor string                // color for edges propagating failures
+
+	Audit         *AuditStore // overlay data edges (store key writer -> reader)
+	DataEdgeColor string      // color for data edges
+}
+
+// dot label line separator
+const dotSep = "\\n"
+
+var defaultStatusColors = map[NodeStatus]string{
+	StatusSucceeded:  "#7FFFD4",
+	StatusFailed:     "#D2042D",
+	StatusSkipped:    "#D3D3D3",
+	StatusBlocked:    "#A9A9A9",
+	StatusTimedOut:   "#FF8C00",
+	StatusRolledBack: "#9370DB",
 }
 
 func DefaultGraphOptions() *GraphOptions {
@@ -41,6 +63,9 @@ func DefaultGraphOptions() *GraphOptions {
 		WeakEdgeStyle:   cgraph.DashedEdgeStyle,
 		ShowGroupInfo:   true,
 		ShowNodeSpec:    true,
+		StatusColors:    maps.Clone(defaultStatusColors),
+		FailEdgeColor:   "#D2042D",
+		DataEdgeColor:   "#1E90FF",
 	}
 }
 
@@ -48,6 +73,9 @@ func (g *Group) RenderGraph(ctx context.Context, opts *GraphOptions, w io.Writer
 	if opts == nil {
 		opts = DefaultGraphOptions()
 	}
+	if err := g.resolve(); err != nil {
+		return err
+	}
 
 	gv, err := graphviz.New(ctx)
 	if err != nil {
@@ -65,15 +93,26 @@ func (g *Group) RenderGraph(ctx context.Context, opts *GraphOptions, w io.Writer
 	if opts.Title != "" {
 		graph.SetLabel(opts.Title)
 	} else if opts.ShowGroupInfo {
-		graph.SetLabel(buildGraphTitle(g))
+		graph.SetLabel(buildGraphTitle(g, dotSep))
 	}
 	graph.SetLabelLocation(cgraph.TopLocation)
 	graph.SetRankDir(opts.RankDir)
 
-	// Create nodes
-	nodeMap := make(map[int]*cgraph.Node)
+	// Create nodes, included nodes are clustered by namespace
+	nodeMap, clusters := make(map[int]*cgraph.Node), make(map[string]*cgraph.Graph)
 	for _, n := range g.nodes {
-		node, err := graph.CreateNodeByName(nodeName(n))
+		parent := graph
+		if n.namespace != "" {
+			if parent = clusters[n.namespace]; parent == nil {
+				if parent, err = graph.CreateSubGraphByName("cluster_" + n.namespace); err != nil {
+					return fmt.Errorf("failed to create subgraph %s: %w", n.namespace, err)
+				}
+				parent.SetLabel(n.namespace)
+				parent.SetStyle(cgraph.RoundedGraphStyle)
+				clusters[n.namespace] = parent
+			}
+		}
+		node, err := parent.CreateNodeByName(nodeName(n))
 		if err != nil {
 			return fmt.Errorf("failed to create node %v: %w", n.key, err)
 		}
@@ -91,9 +130,16 @@ func (g *Group) RenderGraph(ctx context.Context, opts *GraphOptions, w io.Writer
 		default:
 			node.SetFillColor(opts.NodeColor)
 		}
+		label := nodeName(n)
 		if opts.ShowNodeSpec {
-			node.SetLabel(buildNodeLabel(n))
+			label = buildNodeLabel(n, dotSep)
+		}
+		// run results overlay
+		if res, ok := opts.Report.result(n); ok {
+			node.SetFillColor(statusColor(opts, res.Status))
+			label += dotSep + "─────" + dotSep + buildResultLabel(res, dotSep)
 		}
+		node.SetLabel(label)
 		nodeMap[n.idx] = node
 	}
 	// Create edges
@@ -106,6 +152,11 @@ func (g *Group) RenderGraph(ctx context.Context, opts *GraphOptions, w io.Writer
 				return fmt.Errorf("failed to create edge: %w", err)
 			}
 			edge.SetColor(opts.EdgeColor)
+			// failure propagation edge
+			if failEdge(opts.Report, g.nodes[depIdx], n) {
+				edge.SetColor(cmp.Or(opts.FailEdgeColor, defaultStatusColors[StatusFailed]))
+				edge.SetPenWidth(2)
+			}
 			// weak dep
 			if slices.Contains(g.nodes[depIdx].weakTo, n.idx) {
 				edge.SetStyle(opts.WeakEdgeStyle)
@@ -116,6 +167,20 @@ func (g *Group) RenderGraph(ctx context.Context, opts *GraphOptions, w io.Writer
 			}
 		}
 	}
+	// Create data edges
+	if opts.Audit != nil {
+		for i, e := range opts.Audit.dataEdges(g) {
+			edge, err := graph.CreateEdgeByName(fmt.Sprintf("data_%d", i), nodeMap[e.from.idx], nodeMap[e.to.idx])
+			if err != nil {
+				return fmt.Errorf("failed to create data edge: %w", err)
+			}
+			edge.SetColor(cmp.Or(opts.DataEdgeColor, "#1E90FF"))
+			edge.SetStyle(cgraph.DottedEdgeStyle)
+			edge.SetLabel(fmt.Sprint(e.key))
+			edge.SetFontSize(10)
+			edge.SetConstraint(false) // data edges do not affect ranks
+		}
+	}
 	if err := gv.Render(ctx, graph, opts.Format, w); err != nil {
 		return fmt.Errorf("failed to render graph: %w", err)
 	}
@@ -173,7 +238,7 @@ func (g *Group) GraphURL(ctx context.Context, opts *GraphOptions) (string, error
 	return fmt.Sprintf("https://dreampuf.github.io/GraphvizOnline/#%s", url.PathEscape(dot)), nil
 }
 
-func buildGraphTitle(g *Group) string {
+func buildGraphTitle(g *Group, sep string) string {
 	var title = fmt.Sprintf("Group: %s", g.prefix)
 	var infoParts []string
 	if g.limit > 0 {
@@ -195,12 +260,12 @@ func buildGraphTitle(g *Group) string {
 		infoParts = append(infoParts, "log=✓")
 	}
 	if len(infoParts) > 0 {
-		return fmt.Sprintf("%s\\n[%s]", title, strings.Join(infoParts, " | "))
+		return fmt.Sprintf("%s%s[%s]", title, sep, strings.Join(infoParts, " | "))
 	}
 	return title
 }
 
-func buildNodeLabel(n *node) string {
+func buildNodeLabel(n *node, sep string) string {
 	var name = nodeName(n)
 	var details []string
 	if n.ff {
@@ -224,10 +289,44 @@ func buildNodeLabel(n *node) string {
 	if n.timeout > 0 {
 		details = append(details, fmt.Sprintf("⏱ timeout=%s", n.timeout))
 	}
+	if n.cache != nil {
+		details = append(details, "⛁ cache")
+	}
 	if len(details) == 0 {
 		return name
 	}
-	return fmt.Sprintf("%s\\n─────\\n%s", name, strings.Join(details, "\\n"))
+	return name + sep + "─────" + sep + strings.Join(details, sep)
+}
+
+func buildResultLabel(res NodeResult, sep string) string {
+	var details = []string{res.Status.String()}
+	if res.Attempts > 0 {
+		details = append(details, fmt.Sprintf("⧗ %s", res.Duration.Round(time.Microsecond)))
+	}
+	if res.Attempts > 1 {
+		details = append(details, fmt.Sprintf("↻ attempts=%d", res.Attempts))
+	}
+	if res.Cached {
+		details = append(details, "⛁ cached")
+	}
+	return strings.Join(details, sep)
+}
+
+func statusColor(opts *GraphOptions, status NodeStatus) string {
+	if c, ok := opts.StatusColors[status]; ok {
+		return c
+	}
+	return defaultStatusColors[status]
+}
+
+// failEdge reports whether the edge from -> to propagates a failure
+func failEdge(r *Report, from, to *node) bool {
+	fromRes, ok := r.result(from)
+	if !ok || fromRes.Err == nil {
+		return false
+	}
+	toRes, _ := r.result(to)
+	return toRes.Status == StatusBlocked || toRes.Err != nil
 }
 
 func nodeName(n *node) string {
@@ -3,6 +3,7 @@ package group
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"image"
 	"image/png"
@@ -364,6 +365,44 @@ func TestComplexGraphDependencies(t *testing.T) {
 	})
 }
 
+func TestGraphRunResults(t *testing.T) {
+	t.Parallel()
+	rep := NewReport()
+	g := NewGroup().
+		AddRunner(func() error { return nil }).Key("a").
+		AddRunner(func() error { return errors.New("b failed") }).Key("b").Dep("a").
+		AddRunner(func() error { return nil }).Key("c").Dep("b").Group
+	assert.NotNil(t, g.Go(WithReport(context.Background(), rep)))
+
+	opts := DefaultGraphOptions()
+	opts.Report = rep
+	dot, err := g.DOT(context.Background(), opts)
+	assert.Nil(t, err)
+	assert.Contains(t, dot, "succeeded")
+	assert.Contains(t, dot, "failed")
+	assert.Contains(t, dot, "blocked")
+	assert.Contains(t, dot, opts.StatusColors[StatusFailed])
+	assert.Contains(t, dot, opts.StatusColors[StatusBlocked])
+	assert.Contains(t, dot, "penwidth=2") // b -> c propagates failure
+}
+
+func TestGraphDataEdges(t *testing.T) {
+	t.Parallel()
+	audit := NewAuditStore(NewMapStore(), OverwriteAllow)
+	g := NewGroup().
+		AddTask(func(ctx context.Context) error { Put(ctx, "token", 1); return nil }).Key("a").
+		AddTask(func(ctx context.Context) error { Fetch[int](ctx, "token"); return nil }).Key("b").Dep("a").Group
+	assert.Nil(t, g.Go(WithStore(context.Background(), audit)))
+
+	opts := DefaultGraphOptions()
+	opts.Audit = audit
+	dot, err := g.DOT(context.Background(), opts)
+	assert.Nil(t, err)
+	assert.Contains(t, dot, "token")
+	assert.Contains(t, dot, "style=dotted")
+	assert.Contains(t, dot, opts.DataEdgeColor)
+}
+
 func openImage(img image.Image) error {
 	f, err := os.CreateTemp("", "img-*.png")
 	if err != nil {
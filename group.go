@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -12,22 +13,38 @@ import (
 )
 
 type Group struct {
-	x      int
-	nodes  []*node
-	idxMap map[any]int
+	x       int
+	nodes   []*node
+	idxMap  map[any]int
+	mu      sync.Mutex   // guards pending resolution
+	pending []pendingDep // forward-referenced dependencies
 	Options
 }
 
+// pendingDep is a dependency of node idx on a key not added yet
+type pendingDep struct {
+	idx  int
+	key  any
+	weak bool
+}
+
 // Use [Add...] methods to add different types of nodes to the group
 /*
  * [AddAuto...] adds an auto node that returns a value and automatically stores it in the context store
  * CAUTION: will PANIC if auto nodes are not used with storer-context
  */
 func NewGroup(opts ...option) *Group {
+	return newGroup(false, opts...)
+}
+
+func newGroup(builder bool, opts ...option) *Group {
 	g := &Group{
 		nodes:   make([]*node, 0),
 		idxMap:  make(map[any]int),
-		Options: *Opts(opts...),
+		Options: Options{builder: builder},
+	}
+	for _, o := range opts {
+		o(&g.Options)
 	}
 	if g.prefix == "" {
 		g.prefix = "anonymous" // default prefix
@@ -53,6 +70,18 @@ func (g *Group) Go(ctx context.Context, shared ...any) (err error) {
 		}(time.Now())
 	}
 
+	if err = g.Err(); err != nil {
+		return err
+	}
+
+	// futures of the run
+	for _, n := range g.nodes {
+		if n.future != nil {
+			n.future.reset()
+			defer n.future.close()
+		}
+	}
+
 	limit := len(g.nodes) // limit defaults to the number of nodes
 	if g.limit > 0 {
 		limit = g.limit
@@ -70,32 +99,45 @@ func (g *Group) Go(ctx context.Context, shared ...any) (err error) {
 
 	// group pre-execution interceptor
 	if g.pre != nil {
-		if err = g.pre(ctx); err != nil {
+		if err = g.safeRun(ctx, func() error { return g.pre(ctx) }); err != nil {
+			g.repanic(err)
 			return err
 		}
 	}
 	var groupErrs = make([]error, len(g.nodes))
 	var tracker *rollbackTracker
+	var panicked atomic.Pointer[PanicError] // first recovered panic to re-panic
 	var xshared any
 	if len(shared) == 1 {
 		xshared = shared[0]
 	} else if len(shared) > 1 {
 		xshared = shared
 	}
-	g.exec(ctx, eg, xshared, groupErrs, &tracker)
+	g.exec(ctx, eg, xshared, groupErrs, &tracker, &panicked)
 	defer func() {
 		if err == nil {
 			err = leafError(g.nodes, groupErrs)
 		}
 		// group rollback
 		if err != nil && tracker != nil {
-			if rbErr := tracker.rollback(ctx, xshared, groupErrs); rbErr != nil {
+			if rbErr := tracker.rollback(ctx, &g.Options, xshared, groupErrs); rbErr != nil {
+				if p, ok := g.haltOnPanic(rbErr); ok {
+					panicked.CompareAndSwap(nil, p)
+				}
 				err = errors.Join(err, rbErr)
 			}
 		}
 		// group post-execution interceptor
 		if g.after != nil {
-			err = g.after(ctx, err)
+			e := err
+			err = g.safeRun(ctx, func() error { return g.after(ctx, e) })
+			if p, ok := g.haltOnPanic(err); ok {
+				panicked.CompareAndSwap(nil, p)
+			}
+		}
+		// re-panic after cleanup
+		if p := panicked.Load(); p != nil && g.panicPolicy == PanicRepanic {
+			panic(p)
 		}
 	}()
 
@@ -121,7 +163,7 @@ func (g *Group) Go(ctx context.Context, shared ...any) (err error) {
 	return eg.Wait()
 }
 
-func (g *Group) exec(ctx context.Context, eg *errgroup.Group, shared any, groupErrs []error, tracker **rollbackTracker) {
+func (g *Group) exec(ctx context.Context, eg *errgroup.Group, shared any, groupErrs []error, tracker **rollbackTracker, panicked *atomic.Pointer[PanicError]) {
 	var indegree = make([]uint32, len(g.nodes))
 	var rbCnt int
 	for i, node := range g.nodes {
@@ -134,16 +176,66 @@ func (g *Group) exec(ctx context.Context, eg *errgroup.Group, shared any, groupE
 		*tracker = &rollbackTracker{order: make([]*node, rbCnt)}
 	}
 	store, _ := ctx.Value(fetchKey{}).(Storer)
+	audit, _ := store.(*AuditStore)
+	if audit != nil {
+		store = audit.Storer // node writes are recorded by audit views
+	}
+	var await *awaitRun
+	if store != nil {
+		await = newAwaitRun(g)
+		store = &awaitStore{Storer: store, run: await}
+	}
+	var strict *strictRun
+	if g.strict != StrictOff && store != nil {
+		strict = newStrictRun(g, g.strict, store)
+	}
+	obs := observersFrom(ctx)
 	var run func(node *node)
 	run = func(n *node) {
+		obs.notify(evReady, n, 0, nil)
 		eg.Go(func() (err error) {
 			select {
 			case <-ctx.Done(): // ctx check
 				return ctx.Err() // fast-fail triggered or ctx timeout
 			default: // ctx ok
 			}
+			obs.notify(evStart, n, 0, nil)
+
+			// dependency-scoped and audited store views
+			ctx, store := ctx, store
+			var view *strictView
+			if strict != nil {
+				view = strict.view(n)
+				store = view
+			}
+			var audited *auditView
+			if audit != nil {
+				audited = audit.view(n, store)
+				store = audited
+			}
+			if store != nil {
+				ctx = context.WithValue(ctx, fetchKey{}, store)
+			}
+			if await != nil {
+				await.start(n)
+				ctx = context.WithValue(ctx, awaitKey{}, &awaiter{run: await, n: n})
+			}
+			var slot *resultSlot
+			if n.future != nil || n.cache != nil || n.dedup != nil {
+				slot = &resultSlot{}
+				ctx = context.WithValue(ctx, resultSlotKey{}, slot)
+			}
 
 			defer func() {
+				// undeclared store reads
+				if view != nil && err == nil {
+					err = view.Err()
+				}
+				// rejected store overwrites
+				if audited != nil && err == nil {
+					err = audited.Err()
+				}
+
 				// track for rollback
 				if *tracker != nil && n.rollback != nil {
 					(*tracker).track(n)
@@ -151,7 +243,16 @@ func (g *Group) exec(ctx context.Context, eg *errgroup.Group, shared any, groupE
 
 				// node post-execution interceptor
 				if n.after != nil {
-					err = n.after(ctx, shared, err)
+					e := err
+					err = g.safeRun(ctx, func() error { return n.after(ctx, shared, e) })
+				}
+				obs.notify(evEnd, n, 0, err)
+				if await != nil {
+					await.finish(n, err)
+				}
+				if n.future != nil {
+					v, ok := slot.get()
+					n.future.finish(v, ok, err)
 				}
 
 				// error handling
@@ -160,7 +261,11 @@ func (g *Group) exec(ctx context.Context, eg *errgroup.Group, shared any, groupE
 					if !n.sf { // record non-silent-fail error
 						groupErrs[n.idx] = wrapError(n, err, groupErrs)
 					}
-					if n.ff {
+					p, halt := g.haltOnPanic(err)
+					if halt {
+						panicked.CompareAndSwap(nil, p)
+					}
+					if n.ff || halt {
 						if n.sf {
 							err = context.Canceled // sentinel error for silent-fast-fail
 						} else {
@@ -196,11 +301,18 @@ func (g *Group) exec(ctx context.Context, eg *errgroup.Group, shared any, groupE
 			}
 
 			execF := n.f
-			if n.key != nil && store != nil {
+			if n.key != nil && (store != nil || slot != nil) {
 				// wrap store func
 				storeF := execF
 				execF = func(ctx context.Context, shared any) error {
-					return storeF(context.WithValue(ctx, storeKey{}, storeFunc(func(v any) { store.Store(n.key, v) })), shared)
+					return storeF(context.WithValue(ctx, storeKey{}, storeFunc(func(v any) {
+						if slot != nil {
+							slot.set(v)
+						}
+						if store != nil {
+							store.Store(n.key, v)
+						}
+					})), shared)
 				}
 			}
 			if n.retry > 0 {
@@ -216,13 +328,30 @@ func (g *Group) exec(ctx context.Context, eg *errgroup.Group, shared any, groupE
 						if err = retryF(ctx, shared); err == nil {
 							break
 						}
-						if g.log && i < n.retry {
-							slog.InfoContext(ctx, fmt.Sprintf("[Group::node -> exec] group %s: node %s retry #%d", g.prefix, n.key, i+1))
+						if i < n.retry {
+							obs.notify(evRetry, n, i+1, err)
+							if g.log {
+								slog.InfoContext(ctx, fmt.Sprintf("[Group::node -> exec] group %s: node %s retry #%d", g.prefix, n.key, i+1))
+							}
 						}
 					}
 					return
 				}
 			}
+			if n.cache != nil {
+				// wrap cache
+				cacheF := execF
+				execF = func(ctx context.Context, shared any) error {
+					return n.cache.exec(ctx, n, store, obs, cacheF, shared)
+				}
+			}
+			if n.dedup != nil {
+				// wrap singleflight
+				dedupF := execF
+				execF = func(ctx context.Context, shared any) error {
+					return n.dedup.exec(ctx, n, store, dedupF, shared)
+				}
+			}
 			if n.pre != nil {
 				// wrap pre interceptor
 				preF := execF
@@ -237,15 +366,23 @@ func (g *Group) exec(ctx context.Context, eg *errgroup.Group, shared any, groupE
 				}
 			}
 			if n.cond != nil {
-				// wrap condition check (outermost)
+				// wrap condition check
 				condF := execF
 				execF = func(ctx context.Context, shared any) error {
 					if !n.cond(ctx, shared) {
+						obs.notify(evSkip, n, 0, nil)
 						return nil
 					}
 					return condF(ctx, shared)
 				}
 			}
+			if g.profiling(ctx) {
+				// wrap pprof labels (outermost)
+				labelF := execF
+				execF = func(ctx context.Context, shared any) error {
+					return doLabeled(ctx, func(ctx context.Context) error { return labelF(ctx, shared) }, LabelGroup, g.prefix, LabelNode, nodeName(n))
+				}
+			}
 
 			if n.timeout > 0 {
 				var cancel context.CancelFunc
@@ -254,11 +391,12 @@ func (g *Group) exec(ctx context.Context, eg *errgroup.Group, shared any, groupE
 
 				done := make(chan error, 1)
 				go func() {
-					done <- SafeRunNode(ctx, execF, shared)
+					done <- g.safeRunNode(ctx, execF, shared)
 				}()
 				select {
 				case <-ctx.Done():
 					if errors.Is(ctx.Err(), context.DeadlineExceeded) { // actual timeout
+						obs.notify(evTimeout, n, 0, nil)
 						if g.log {
 							slog.InfoContext(ctx, fmt.Sprintf("[Group::node -> exec] group %s: node %s timeout", g.prefix, n.key), slog.Duration("after", g.timeout))
 						}
@@ -269,7 +407,7 @@ func (g *Group) exec(ctx context.Context, eg *errgroup.Group, shared any, groupE
 					return
 				}
 			}
-			return SafeRunNode(ctx, execF, shared)
+			return g.safeRunNode(ctx, execF, shared)
 		})
 	}
 
@@ -2,7 +2,9 @@ package group
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 )
 
 func (g *Group) addNode(f func(context.Context, any) error) *node {
@@ -294,6 +296,12 @@ func (g *Group) Verify(panicking bool) string {
 	if len(g.nodes) == 0 {
 		return ""
 	}
+	if err := g.resolve(); err != nil {
+		if panicking {
+			panic(err.Error())
+		}
+		return err.Error()
+	}
 	type token = struct{}
 	graph, src := make(map[any][]any, len(g.nodes)), make(map[any]token, len(g.nodes))
 	for _, node := range g.nodes {
@@ -346,3 +354,119 @@ func (g *Group) Verify(panicking bool) string {
 	}
 	return ""
 }
+
+// resolve links pending dependencies whose upstream has been added,
+// returns all still unresolved dependencies joined as ErrMissingDependency
+/*
+ * every reader of the graph (runs, validation, rendering, cloning) resolves first,
+ * so edges are linked once under g.mu before any unlocked read
+ */
+func (g *Group) resolve() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.pending) == 0 {
+		return nil
+	}
+	var errs []error
+	var unresolved []pendingDep
+	for _, p := range g.pending {
+		n := g.nodes[p.idx]
+		idx, ok := g.idxMap[p.key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w %q -> %q", ErrMissingDependency, n.key, p.key))
+			unresolved = append(unresolved, p)
+			continue
+		}
+		n.link(idx, p.weak)
+	}
+	g.pending = unresolved
+	return errors.Join(errs...)
+}
+
+// Levels returns node keys grouped by topological level (anonymous nodes are reported as nil)
+/*
+ * nodes in the same level have no dependencies on each other and may run concurrently,
+ * level i nodes depend only on nodes of levels < i
+ * returns ErrMissingDependency if dependencies are unresolved, ErrCycle if the dependency graph is not acyclic
+ */
+func (g *Group) Levels() ([][]any, error) {
+	if err := g.resolve(); err != nil {
+		return nil, err
+	}
+	level, err := g.levels()
+	if err != nil {
+		return nil, err
+	}
+	var levels [][]any
+	for idx, l := range level {
+		for len(levels) <= l {
+			levels = append(levels, nil)
+		}
+		levels[l] = append(levels[l], g.nodes[idx].key)
+	}
+	return levels, nil
+}
+
+// CriticalPath returns node keys of the longest dependency chain (anonymous nodes are reported as nil)
+/*
+ * the chain length is its number of nodes, nodes are not weighted by duration
+ * returns ErrMissingDependency if dependencies are unresolved, ErrCycle if the dependency graph is not acyclic
+ */
+func (g *Group) CriticalPath() ([]any, error) {
+	if err := g.resolve(); err != nil {
+		return nil, err
+	}
+	level, err := g.levels()
+	if err != nil || len(level) == 0 {
+		return nil, err
+	}
+	// deepest node, then walk back through the deepest upstreams
+	cur := 0
+	for idx, l := range level {
+		if l > level[cur] {
+			cur = idx
+		}
+	}
+	path := []any{g.nodes[cur].key}
+	for level[cur] > 0 {
+		for _, depIdx := range g.nodes[cur].deps {
+			if level[depIdx] == level[cur]-1 {
+				cur = depIdx
+				break
+			}
+		}
+		path = append(path, g.nodes[cur].key)
+	}
+	slices.Reverse(path)
+	return path, nil
+}
+
+// levels returns the topological level of each node (longest path from a root)
+func (g *Group) levels() ([]int, error) {
+	indegree, level := make([]int, len(g.nodes)), make([]int, len(g.nodes))
+	queue := make([]int, 0, len(g.nodes))
+	for i, n := range g.nodes {
+		if indegree[i] = len(n.deps); indegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for i := 0; i < len(queue); i++ {
+		n := g.nodes[queue[i]]
+		for _, toIdx := range n.to {
+			level[toIdx] = max(level[toIdx], level[n.idx]+1)
+			if indegree[toIdx]--; indegree[toIdx] == 0 {
+				queue = append(queue, toIdx)
+			}
+		}
+	}
+	if len(queue) < len(g.nodes) {
+		var stuck []string
+		for i, d := range indegree {
+			if d > 0 {
+				stuck = append(stuck, nodeName(g.nodes[i]))
+			}
+		}
+		return nil, fmt.Errorf("%w: unresolvable nodes %v", ErrCycle, stuck)
+	}
+	return level, nil
+}
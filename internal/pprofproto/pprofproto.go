@@ -0,0 +1,176 @@
+// Package pprofproto decodes the sample types, values and string labels of gzipped pprof cpu profiles
+/*
+ * only the fields used by the node summary of Group.Profile are decoded,
+ * see profile.proto of github.com/google/pprof for the wire format
+ */
+package pprofproto
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrMalformed is returned for profiles that are not valid gzipped pprof protobuf
+var ErrMalformed = errors.New("malformed profile")
+
+// Profile is the part of a pprof profile needed for per-label summaries
+type Profile struct {
+	Units   []string // sample value units
+	Samples []Sample
+}
+
+type Sample struct {
+	Values []int64
+	Labels map[string][]string // string labels
+}
+
+// Parse decodes sample types and samples of a gzipped pprof profile
+/*
+ * string table references are resolved after decoding since the table may follow the samples
+ */
+func Parse(data []byte) (*Profile, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+	if data, err = io.ReadAll(zr); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	var strs []string
+	var units []int64
+	type rawSample struct {
+		values []int64
+		labels [][2]int64 // key and str indexes
+	}
+	var samples []rawSample
+	err = fields(data, func(num, typ int, v uint64, b []byte) error {
+		switch {
+		case num == 1 && typ == 2: // sample_type
+			var unit int64
+			err := fields(b, func(num, typ int, v uint64, _ []byte) error {
+				if num == 2 && typ == 0 {
+					unit = int64(v)
+				}
+				return nil
+			})
+			units = append(units, unit)
+			return err
+		case num == 2 && typ == 2: // sample
+			var s rawSample
+			err := fields(b, func(num, typ int, v uint64, b []byte) error {
+				switch {
+				case num == 2 && typ == 0:
+					s.values = append(s.values, int64(v))
+				case num == 2 && typ == 2: // packed values
+					for len(b) > 0 {
+						v, n := binary.Uvarint(b)
+						if n <= 0 {
+							return ErrMalformed
+						}
+						s.values, b = append(s.values, int64(v)), b[n:]
+					}
+				case num == 3 && typ == 2: // label
+					var l [2]int64
+					err := fields(b, func(num, typ int, v uint64, _ []byte) error {
+						if (num == 1 || num == 2) && typ == 0 {
+							l[num-1] = int64(v)
+						}
+						return nil
+					})
+					s.labels = append(s.labels, l)
+					return err
+				}
+				return nil
+			})
+			samples = append(samples, s)
+			return err
+		case num == 6 && typ == 2: // string_table
+			strs = append(strs, string(b))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	str := func(i int64) (string, error) {
+		if i < 0 || i >= int64(len(strs)) {
+			return "", ErrMalformed
+		}
+		return strs[i], nil
+	}
+	p := &Profile{Units: make([]string, len(units)), Samples: make([]Sample, len(samples))}
+	for i, unit := range units {
+		if p.Units[i], err = str(unit); err != nil {
+			return nil, err
+		}
+	}
+	for i, s := range samples {
+		p.Samples[i] = Sample{Values: s.values, Labels: make(map[string][]string, len(s.labels))}
+		for _, l := range s.labels {
+			if l[1] == 0 { // numeric label
+				continue
+			}
+			key, err := str(l[0])
+			if err != nil {
+				return nil, err
+			}
+			value, err := str(l[1])
+			if err != nil {
+				return nil, err
+			}
+			p.Samples[i].Labels[key] = append(p.Samples[i].Labels[key], value)
+		}
+	}
+	return p, nil
+}
+
+// fields calls f with the number, wire type and payload of each field of a protobuf message
+/*
+ * v holds varint and fixed payloads, b holds length-delimited payloads
+ */
+func fields(data []byte, f func(num, typ int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return ErrMalformed
+		}
+		data = data[n:]
+		var v uint64
+		var b []byte
+		switch tag & 7 {
+		case 0:
+			if v, n = binary.Uvarint(data); n <= 0 {
+				return ErrMalformed
+			}
+			data = data[n:]
+		case 1:
+			if len(data) < 8 {
+				return ErrMalformed
+			}
+			v, data = binary.LittleEndian.Uint64(data), data[8:]
+		case 2:
+			l, n := binary.Uvarint(data)
+			if n <= 0 || l > uint64(len(data)-n) {
+				return ErrMalformed
+			}
+			b, data = data[n:n+int(l)], data[n+int(l):]
+		case 5:
+			if len(data) < 4 {
+				return ErrMalformed
+			}
+			v, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		default:
+			return ErrMalformed
+		}
+		if err := f(int(tag>>3), int(tag&7), v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
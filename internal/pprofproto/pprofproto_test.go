@@ -0,0 +1,119 @@
+package pprofproto
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// wire format helpers
+
+func tag(num, typ int) []byte { return binary.AppendUvarint(nil, uint64(num<<3|typ)) }
+
+func varintField(num int, v uint64) []byte { return binary.AppendUvarint(tag(num, 0), v) }
+
+func bytesField(num int, parts ...[]byte) []byte {
+	b := bytes.Join(parts, nil)
+	return append(binary.AppendUvarint(tag(num, 2), uint64(len(b))), b...)
+}
+
+func packed(vs ...uint64) []byte {
+	var b []byte
+	for _, v := range vs {
+		b = binary.AppendUvarint(b, v)
+	}
+	return b
+}
+
+func gz(parts ...[]byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(bytes.Join(parts, nil))
+	_ = zw.Close()
+	return buf.Bytes()
+}
+
+func stringTable(strs ...string) []byte {
+	var b []byte
+	for _, s := range strs {
+		b = append(b, bytesField(6, []byte(s))...)
+	}
+	return b
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	// indexes 1 samples, 2 count, 3 cpu, 4 nanoseconds, 5 node, 6 a, 7 b
+	table := stringTable("", "samples", "count", "cpu", "nanoseconds", "node", "a", "b")
+	valid := [][]byte{
+		bytesField(1, varintField(1, 1), varintField(2, 2)), // samples/count
+		bytesField(1, varintField(1, 3), varintField(2, 4)), // cpu/nanoseconds
+		bytesField(2, // packed values and a string label
+			bytesField(1, packed(1, 2)), // location ids
+			bytesField(2, packed(1, 10_000_000)),
+			bytesField(3, varintField(1, 5), varintField(2, 6)),
+			bytesField(3, varintField(1, 5), varintField(3, 42)), // numeric label
+		),
+		bytesField(2, // unpacked values
+			varintField(2, 2),
+			varintField(2, 20_000_000),
+			bytesField(3, varintField(1, 5), varintField(2, 7)),
+		),
+		varintField(12, 10_000_000), // period
+		table,                       // string table after the samples
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		p, err := Parse(gz(valid...))
+		assert.NoError(t, err)
+		assert.Equal(t, &Profile{
+			Units: []string{"count", "nanoseconds"},
+			Samples: []Sample{
+				{Values: []int64{1, 10_000_000}, Labels: map[string][]string{"node": {"a"}}},
+				{Values: []int64{2, 20_000_000}, Labels: map[string][]string{"node": {"b"}}},
+			},
+		}, p)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		p, err := Parse(gz())
+		assert.NoError(t, err)
+		assert.Equal(t, &Profile{Units: []string{}, Samples: []Sample{}}, p)
+	})
+
+	malformed := []struct {
+		name string
+		data []byte
+	}{
+		{"not gzipped", []byte("profile")},
+		{"truncated gzip", gz(valid...)[:20]},
+		{"truncated tag", gz([]byte{0x80})},
+		{"truncated varint", gz(tag(9, 0), []byte{0xff, 0xff})},
+		{"length beyond data", gz(tag(6, 2), []byte{5, 'a', 'b'})},
+		{"truncated fixed64", gz(tag(9, 1), []byte{1, 2, 3})},
+		{"truncated fixed32", gz(tag(9, 5), []byte{1, 2})},
+		{"unknown wire type", gz(tag(9, 3))},
+		{"truncated nested message", gz(bytesField(1, []byte{0x80}))},
+		{"truncated packed values", gz(bytesField(2, bytesField(2, packed(1), []byte{0x80})), table)},
+		{"truncated label", gz(bytesField(2, bytesField(3, tag(1, 0))), table)},
+		{"unit index out of range", gz(bytesField(1, varintField(2, 8)), table)},
+		{"label key index out of range", gz(bytesField(2, bytesField(3, varintField(1, 8), varintField(2, 6))), table)},
+		{"label value index out of range", gz(bytesField(2, bytesField(3, varintField(1, 5), varintField(2, 8))), table)},
+		{"negative string index", gz(bytesField(1, varintField(2, 1<<63)), table)},
+		{"missing string table", gz(valid[:4]...)},
+	}
+	for _, c := range malformed {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			p, err := Parse(c.data)
+			assert.ErrorIs(t, err, ErrMalformed)
+			assert.Nil(t, p)
+		})
+	}
+}
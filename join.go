@@ -0,0 +1,118 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var ErrErrorThreshold = errors.New("error threshold reached")
+
+// joinedErrs collects func errors by index in join mode
+type joinedErrs struct {
+	opts   *Options
+	cancel context.CancelCauseFunc
+
+	mu       sync.Mutex
+	errs     []error
+	failures int
+	halted   bool
+}
+
+// errgroup returns the errgroup of n funcs, the first error cancels the context unless in join mode
+func (o *Options) errgroup(ctx context.Context, n int) (*errgroup.Group, context.Context, *joinedErrs) {
+	if !o.joinErrs {
+		g, ctx := errgroup.WithContext(ctx)
+		return g, ctx, nil
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &errgroup.Group{}, ctx, &joinedErrs{opts: o, cancel: cancel, errs: make([]error, n)}
+}
+
+// record records the error of func i, cancels the remaining funcs once the threshold is reached
+func (j *joinedErrs) record(i int, err error) {
+	if j == nil || err == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.grow(i)
+	j.errs[i] = fmt.Errorf("func #%d: %w", i, err)
+	j.failures++
+	if _, halt := j.opts.haltOnPanic(err); halt {
+		j.cancel(err)
+	} else if j.opts.threshold > 0 && j.failures >= j.opts.threshold && !j.halted {
+		j.halted = true
+		j.cancel(fmt.Errorf("%w: %d failures", ErrErrorThreshold, j.failures))
+	}
+}
+
+// skip records func i as not run
+func (j *joinedErrs) skip(i int) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.grow(i)
+	j.errs[i] = ErrNotRun
+}
+
+// grow extends errs to hold func i (streams of unknown length), j.mu must be held
+func (j *joinedErrs) grow(i int) {
+	if i >= len(j.errs) {
+		j.errs = append(j.errs, make([]error, i+1-len(j.errs))...)
+	}
+}
+
+// wait returns the joined errors of failed funcs as *ResultsError in join mode, err otherwise
+/*
+ * the threshold error leads the joined errors if reached,
+ * if no func failed but some did not run, the cancellation cause is returned
+ */
+func (j *joinedErrs) wait(ctx context.Context, err error) error {
+	if j == nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var failed []error
+	if j.halted {
+		failed = append(failed, context.Cause(ctx))
+	}
+	skipped := false
+	for _, err := range j.errs {
+		switch {
+		case errors.Is(err, ErrNotRun):
+			skipped = true
+		case err != nil:
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) == 0 {
+		if !skipped {
+			return nil
+		}
+		failed = append(failed, context.Cause(ctx))
+	}
+	return &ResultsError{Errs: j.errs, err: errors.Join(failed...)}
+}
+
+// close releases the context of the join mode
+func (j *joinedErrs) close() {
+	if j != nil {
+		j.cancel(nil)
+	}
+}
+
+// setLen sets the number of funcs of a stream
+func (j *joinedErrs) setLen(n int) {
+	if j != nil && n > 0 {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		j.grow(n - 1)
+	}
+}
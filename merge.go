@@ -0,0 +1,196 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NamespacedKey is the qualified key of a node included under a namespace
+/*
+ * nodes of other parts depend on included nodes by qualified keys, e.g. Dep(NS("billing", "charge"))
+ */
+type NamespacedKey struct {
+	Namespace string
+	Key       any
+}
+
+// NS returns the qualified key of key under namespace
+func NS(namespace string, key any) NamespacedKey {
+	return NamespacedKey{Namespace: namespace, Key: key}
+}
+
+func (k NamespacedKey) String() string {
+	return fmt.Sprintf("%s/%v", k.Namespace, k.Key)
+}
+
+// ConflictPolicy resolves conflicting group options (limit, timeout, pre, after) when including groups
+/*
+ * options set by only one of the groups are never in conflict and are always taken
+ * other options (log, pprof labels, panic policy, error collector) of the including group apply
+ */
+type ConflictPolicy uint8
+
+const (
+	ConflictError    ConflictPolicy = iota // fail on conflicting options
+	ConflictKeep                           // keep options of the including group
+	ConflictOverride                       // take options of the included group
+	ConflictCombine                        // sum limits, take the longer timeout, chain pre / after funcs in including order
+)
+
+// Include copies nodes of other into the group under namespace (empty namespace keeps keys unqualified)
+/*
+ * keys are qualified as NS(namespace, key), dependencies within other are kept,
+ * dependencies on keys other could not resolve are kept as is and resolved against the whole group
+ * key collisions and option conflicts PANIC (or are accumulated in builder mode)
+ */
+func (g *Group) Include(other *Group, namespace string, policy ...ConflictPolicy) *Group {
+	p := ConflictError
+	if len(policy) > 0 {
+		p = policy[0]
+	}
+	if err := g.include(other, namespace, p); err != nil {
+		g.failErr(err)
+	}
+	return g
+}
+
+// Merge merges groups into a new group, each group is namespaced by its prefix
+/*
+ * groups sharing a prefix (e.g. the default "anonymous") are namespaced by position as prefix#i
+ */
+func Merge(policy ConflictPolicy, groups ...*Group) (*Group, error) {
+	prefixes := make([]string, 0, len(groups))
+	count := make(map[string]int, len(groups))
+	for _, other := range groups {
+		prefixes = append(prefixes, other.prefix)
+		count[other.prefix]++
+	}
+	g := NewGroup(WithPrefix(strings.Join(prefixes, "+")))
+	for i, other := range groups {
+		namespace := other.prefix
+		if count[namespace] > 1 {
+			namespace = fmt.Sprintf("%s#%d", namespace, i)
+		}
+		if err := g.include(other, namespace, policy); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func (g *Group) include(other *Group, namespace string, policy ConflictPolicy) error {
+	if other == g {
+		return fmt.Errorf("%w: group %q includes itself", ErrInvalidGroup, g.prefix)
+	}
+	qualify := func(key any) any {
+		if key == nil || namespace == "" {
+			return key
+		}
+		return NS(namespace, key)
+	}
+	// check collisions before any change
+	for _, n := range other.nodes {
+		if key := qualify(n.key); key != nil {
+			if _, ok := g.idxMap[key]; ok {
+				return fmt.Errorf("%w: include %q: duplicate node key %q", ErrInvalidGroup, namespace, key)
+			}
+		}
+	}
+	if err := g.mergeOptions(&other.Options, policy); err != nil {
+		return fmt.Errorf("include %q: %w", namespace, err)
+	}
+
+	_ = other.resolve() // unresolved dependencies are resolved against the whole group
+	offset := len(g.nodes)
+	for _, n := range other.nodes {
+		copied := g.addNode(n.f)
+		copied.nodeSpec, copied.namespace = n.nodeSpec, joinNamespace(namespace, n.namespace)
+		if n.dedup != nil {
+			// included copies do not share in-flight executions with the other group
+			copied.Dedup(n.dedup.keyFunc)
+		}
+		if key := qualify(n.key); key != nil {
+			copied.key, g.idxMap[key] = key, copied.idx
+		}
+	}
+	for _, n := range other.nodes {
+		copied := g.nodes[offset+n.idx]
+		for _, depIdx := range n.deps {
+			dep := other.nodes[depIdx]
+			copied.link(offset+depIdx, slices.Contains(dep.weakTo, n.idx))
+		}
+	}
+	other.mu.Lock()
+	for _, p := range other.pending {
+		g.pending = append(g.pending, pendingDep{idx: offset + p.idx, key: p.key, weak: p.weak})
+	}
+	other.mu.Unlock()
+	g.errs = append(g.errs, other.errs...)
+	return nil
+}
+
+// mergeOptions merges conflicting options of other into the group by policy
+func (g *Group) mergeOptions(other *Options, policy ConflictPolicy) error {
+	var conflicts []string
+	if g.limit > 0 && other.limit > 0 && g.limit != other.limit {
+		conflicts = append(conflicts, fmt.Sprintf("limit %d != %d", g.limit, other.limit))
+	}
+	if g.timeout > 0 && other.timeout > 0 && g.timeout != other.timeout {
+		conflicts = append(conflicts, fmt.Sprintf("timeout %s != %s", g.timeout, other.timeout))
+	}
+	if g.pre != nil && other.pre != nil {
+		conflicts = append(conflicts, "pre funcs")
+	}
+	if g.after != nil && other.after != nil {
+		conflicts = append(conflicts, "after funcs")
+	}
+	if len(conflicts) > 0 && policy == ConflictError {
+		return fmt.Errorf("%w: conflicting options: %s", ErrInvalidGroup, strings.Join(conflicts, ", "))
+	}
+
+	switch {
+	case g.limit == 0 || policy == ConflictOverride && other.limit > 0:
+		g.limit = other.limit
+	case policy == ConflictCombine && other.limit > 0:
+		g.limit += other.limit
+	}
+	switch {
+	case g.timeout == 0 || policy == ConflictOverride && other.timeout > 0:
+		g.timeout = other.timeout
+	case policy == ConflictCombine:
+		g.timeout = max(g.timeout, other.timeout)
+	}
+	switch pre, otherPre := g.pre, other.pre; {
+	case pre == nil || policy == ConflictOverride && otherPre != nil:
+		g.pre = otherPre
+	case policy == ConflictCombine && otherPre != nil:
+		g.pre = func(ctx context.Context) error {
+			if err := pre(ctx); err != nil {
+				return err
+			}
+			return otherPre(ctx)
+		}
+	}
+	switch after, otherAfter := g.after, other.after; {
+	case after == nil || policy == ConflictOverride && otherAfter != nil:
+		g.after = otherAfter
+	case policy == ConflictCombine && otherAfter != nil:
+		g.after = func(ctx context.Context, err error) error {
+			return otherAfter(ctx, after(ctx, err))
+		}
+	}
+	return nil
+}
+
+// joinNamespace qualifies a nested namespace
+func joinNamespace(namespace, nested string) string {
+	switch {
+	case namespace == "":
+		return nested
+	case nested == "":
+		return namespace
+	}
+	return namespace + "/" + nested
+}
@@ -0,0 +1,139 @@
+package group
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-graphviz/cgraph"
+)
+
+// mermaid label line separator
+const mermaidSep = "<br/>"
+
+// Mermaid returns the group graph as a Mermaid flowchart
+/*
+ * mirrors RenderGraph: strong edges are solid, weak edges are dashed,
+ * fast-fail / silent-fail nodes and run results (GraphOptions.Report) are styled by classDef
+ * Format and NodeShape are ignored, nodes are rendered as boxes
+ * unresolved dependencies are omitted
+ */
+func (g *Group) Mermaid(opts *GraphOptions) string {
+	if opts == nil {
+		opts = DefaultGraphOptions()
+	}
+	_ = g.resolve()
+	var b strings.Builder
+
+	// title front matter
+	title := opts.Title
+	if title == "" && opts.ShowGroupInfo {
+		title = buildGraphTitle(g, " ")
+	}
+	if title != "" {
+		fmt.Fprintf(&b, "---\ntitle: %s\n---\n", strconv.Quote(title)) // go escapes are valid in yaml double-quoted strings
+	}
+	fmt.Fprintf(&b, "flowchart %s\n", cmp.Or(opts.RankDir, cgraph.TBRank))
+
+	// class definitions
+	fmt.Fprintf(&b, "    classDef default fill:%s,stroke:%s,color:black\n", cmp.Or(opts.NodeColor, "#7FFFD4"), cmp.Or(opts.EdgeColor, "#333333"))
+	fmt.Fprintf(&b, "    classDef fastFail fill:%s,color:black\n", cmp.Or(opts.FastFailColor, "#D2042D"))
+	fmt.Fprintf(&b, "    classDef silentFail fill:%s,color:black\n", cmp.Or(opts.SilentFailColor, "#A9A9A9"))
+	fmt.Fprintf(&b, "    classDef silentFastFail fill:%s,stroke:%s,stroke-width:3px,color:black\n", cmp.Or(opts.SilentFailColor, "#A9A9A9"), cmp.Or(opts.FastFailColor, "#D2042D"))
+	if opts.Report != nil {
+		for _, status := range []NodeStatus{StatusBlocked, StatusSucceeded, StatusFailed, StatusSkipped, StatusTimedOut, StatusRolledBack} {
+			fmt.Fprintf(&b, "    classDef %s fill:%s,color:black\n", statusClass(status), statusColor(opts, status))
+		}
+	}
+
+	// nodes, included nodes are grouped in subgraphs by namespace
+	classes := make(map[string][]string)
+	var namespaces []string
+	for _, n := range g.nodes {
+		if !slices.Contains(namespaces, n.namespace) {
+			namespaces = append(namespaces, n.namespace)
+		}
+	}
+	for i, ns := range namespaces {
+		indent := "    "
+		if ns != "" {
+			fmt.Fprintf(&b, "    subgraph ns%d[\"%s\"]\n", i, mermaidEscape(ns))
+			indent += "    "
+		}
+		for _, n := range g.nodes {
+			if n.namespace != ns {
+				continue
+			}
+			label := nodeName(n)
+			if opts.ShowNodeSpec {
+				label = buildNodeLabel(n, mermaidSep)
+			}
+			class := ""
+			switch {
+			case n.ff && n.sf:
+				class = "silentFastFail"
+			case n.ff:
+				class = "fastFail"
+			case n.sf:
+				class = "silentFail"
+			}
+			// run results overlay
+			if res, ok := opts.Report.result(n); ok {
+				class = statusClass(res.Status)
+				label += mermaidSep + "─────" + mermaidSep + buildResultLabel(res, mermaidSep)
+			}
+			fmt.Fprintf(&b, "%s%s[\"%s\"]\n", indent, mermaidID(n), mermaidEscape(label))
+			if class != "" {
+				classes[class] = append(classes[class], mermaidID(n))
+			}
+		}
+		if ns != "" {
+			fmt.Fprintf(&b, "    end\n")
+		}
+	}
+
+	// edges
+	var edge int
+	var failEdges []string
+	for _, n := range g.nodes {
+		for _, depIdx := range n.deps {
+			from := g.nodes[depIdx]
+			if slices.Contains(from.weakTo, n.idx) {
+				fmt.Fprintf(&b, "    %s -.->|weak| %s\n", mermaidID(from), mermaidID(n))
+			} else {
+				fmt.Fprintf(&b, "    %s --> %s\n", mermaidID(from), mermaidID(n))
+			}
+			if failEdge(opts.Report, from, n) {
+				failEdges = append(failEdges, fmt.Sprint(edge))
+			}
+			edge++
+		}
+	}
+
+	// styles
+	for _, class := range slices.Sorted(maps.Keys(classes)) {
+		fmt.Fprintf(&b, "    class %s %s\n", strings.Join(classes[class], ","), class)
+	}
+	if edge > 0 && opts.EdgeColor != "" {
+		fmt.Fprintf(&b, "    linkStyle default stroke:%s\n", opts.EdgeColor)
+	}
+	if len(failEdges) > 0 {
+		fmt.Fprintf(&b, "    linkStyle %s stroke:%s,stroke-width:2px\n", strings.Join(failEdges, ","), cmp.Or(opts.FailEdgeColor, defaultStatusColors[StatusFailed]))
+	}
+	return b.String()
+}
+
+func mermaidID(n *node) string {
+	return fmt.Sprintf("n%d", n.idx)
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;").Replace(s)
+}
+
+func statusClass(s NodeStatus) string {
+	return strings.ReplaceAll(s.String(), " ", "")
+}
@@ -0,0 +1,80 @@
+package group
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-graphviz/cgraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMermaid(t *testing.T) {
+	t.Parallel()
+
+	t.Run("basic flowchart", func(t *testing.T) {
+		g := NewGroup().
+			AddRunner(func() error { return nil }).Key("a").
+			AddRunner(func() error { return nil }).Key("b").Dep("a").
+			AddRunner(func() error { return nil }).Key("c").WeakDep("a").
+			AddRunner(func() error { return nil }).Key("d").Dep("b", "c").Group
+
+		opts := &GraphOptions{RankDir: cgraph.LRRank}
+		assert.Equal(t, `flowchart LR
+    classDef default fill:#7FFFD4,stroke:#333333,color:black
+    classDef fastFail fill:#D2042D,color:black
+    classDef silentFail fill:#A9A9A9,color:black
+    classDef silentFastFail fill:#A9A9A9,stroke:#D2042D,stroke-width:3px,color:black
+    n0["a"]
+    n1["b"]
+    n2["c"]
+    n3["d"]
+    n0 --> n1
+    n0 -.->|weak| n2
+    n1 --> n3
+    n2 --> n3
+`, g.Mermaid(opts))
+	})
+
+	t.Run("node spec and fail strategies", func(t *testing.T) {
+		g := NewGroup(WithPrefix("spec"), WithLimit(2)).
+			AddRunner(func() error { return nil }).Key("a").FastFail().WithRetry(2).
+			AddRunner(func() error { return nil }).Key(`"b"`).SilentFail().Dep("a").
+			AddRunner(func() error { return nil }).Key("c").FastFail().SilentFail().WithTimeout(time.Second).Group
+
+		m := g.Mermaid(nil)
+		assert.Contains(t, m, `title: "Group: spec [limit=2]"`)
+		assert.Contains(t, m, "flowchart TB")
+		assert.Contains(t, m, `n0["a<br/>─────<br/>⚡︎ fast-fail<br/>↻ retry=2"]`)
+		assert.Contains(t, m, `n1["#quot;b#quot;<br/>─────<br/>⊘ silent-fail"]`)
+		assert.Contains(t, m, "class n0 fastFail")
+		assert.Contains(t, m, "class n1 silentFail")
+		assert.Contains(t, m, "class n2 silentFastFail")
+		assert.Contains(t, m, "linkStyle default stroke:#333333")
+	})
+
+	t.Run("quoted title", func(t *testing.T) {
+		g := NewGroup().AddRunner(func() error { return nil }).Key("a").Group
+		m := g.Mermaid(&GraphOptions{Title: `say "hi": #1`})
+		assert.Contains(t, m, "---\ntitle: \"say \\\"hi\\\": #1\"\n---\n")
+	})
+
+	t.Run("namespace subgraphs", func(t *testing.T) {
+		f := func() error { return nil }
+		billing := NewGroup(WithPrefix("billing")).AddRunner(f).Key("charge").Group
+		g := NewGroup().
+			AddRunner(f).Key("order").Group.
+			Include(billing, "billing").
+			AddRunner(f).Key("notify").Dep(NS("billing", "charge")).Group
+		g.Node(NS("billing", "charge")).Dep("order")
+
+		m := g.Mermaid(&GraphOptions{RankDir: cgraph.LRRank})
+		assert.Contains(t, m, `    n0["order"]
+    n2["notify"]
+    subgraph ns1["billing"]
+        n1["billing/charge"]
+    end
+`)
+		assert.Contains(t, m, "n1 --> n2")
+		assert.Contains(t, m, "n0 --> n1")
+	})
+}
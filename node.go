@@ -3,6 +3,7 @@ package group
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -27,6 +28,8 @@ type node struct {
 	key              any
 	deps, to, weakTo []int // dependencies | to nodes | weak to nodes
 	f                func(ctx context.Context, shared any) error
+	namespace        string     // namespace of included nodes
+	future           futureHook // typed result of future nodes
 	nodeSpec
 	*Group
 }
@@ -46,48 +49,75 @@ type nodeSpec struct {
 	after    NodeAfterFunc
 	rollback NodeRollbackFunc
 	timeout  time.Duration
+	cache    *nodeCache
+	dedup    *nodeDedup
 }
 
 func (n *node) Key(key any) *node {
 	if _, ok := n.idxMap[key]; ok {
-		panic(fmt.Sprintf("duplicate node key %q", key))
+		n.fail(fmt.Sprintf("duplicate node key %q", key))
+		return n
 	}
 	n.key, n.idxMap[key] = key, n.idx
 	return n
 }
 
+// Dep adds strong dependencies by key
+/*
+ * dependencies on nodes not added yet are resolved lazily when the group runs (or verifies),
+ * unresolved keys are reported together as ErrMissingDependency
+ */
 func (n *node) Dep(keys ...any) *node {
 	for _, key := range keys {
-		idx, ok := n.idxMap[key]
-		if !ok {
-			panic(fmt.Sprintf("missing dependency %q -> %q", n.key, key))
-		}
-		for _, depIdx := range n.deps {
-			if depIdx == idx {
-				panic(fmt.Sprintf("duplicate dependency %q -> %q", n.key, key))
-			}
-		}
-		n.deps, n.nodes[idx].to = append(n.deps, idx), append(n.nodes[idx].to, n.idx)
+		n.dep(key, false)
 	}
 	return n
 }
 
+// WeakDep adds weak dependencies by key, resolved lazily as Dep
 func (n *node) WeakDep(keys ...any) *node {
 	for _, key := range keys {
-		idx, ok := n.idxMap[key]
-		if !ok {
-			panic(fmt.Sprintf("missing dependency %q -> %q", n.key, key))
-		}
-		for _, depIdx := range n.deps {
-			if depIdx == idx {
-				panic(fmt.Sprintf("duplicate dependency %q -> %q", n.key, key))
-			}
-		}
-		n.deps, n.nodes[idx].to, n.nodes[idx].weakTo = append(n.deps, idx), append(n.nodes[idx].to, n.idx), append(n.nodes[idx].weakTo, n.idx)
+		n.dep(key, true)
 	}
 	return n
 }
 
+func (n *node) dep(key any, weak bool) {
+	idx, ok := n.idxMap[key]
+	dup := slices.ContainsFunc(n.pending, func(p pendingDep) bool { return p.idx == n.idx && p.key == key })
+	if ok {
+		dup = dup || slices.Contains(n.deps, idx)
+	}
+	if dup {
+		n.fail(fmt.Sprintf("duplicate dependency %q -> %q", n.key, key))
+		return
+	}
+	if !ok {
+		n.pending = append(n.pending, pendingDep{idx: n.idx, key: key, weak: weak})
+		return
+	}
+	n.link(idx, weak)
+}
+
+// link the dependency on node idx
+func (n *node) link(idx int, weak bool) {
+	n.deps, n.nodes[idx].to = append(n.deps, idx), append(n.nodes[idx].to, n.idx)
+	if weak {
+		n.nodes[idx].weakTo = append(n.nodes[idx].weakTo, n.idx)
+	}
+}
+
+// WithFunc replaces the node func, f is any func type accepted by [Add...] methods or a Node / AutoNode
+func (n *node) WithFunc(f any) *node {
+	nf, ok := nodeFunc(f)
+	if !ok {
+		n.fail(fmt.Sprintf("unsupported func type %T", f))
+		return n
+	}
+	n.f = nf
+	return n
+}
+
 func (n *node) FastFail() *node {
 	n.ff = true
 	return n
@@ -100,7 +130,8 @@ func (n *node) SilentFail() *node {
 
 func (n *node) WithRetry(times int) *node {
 	if times < 0 {
-		panic("retry times must be non-negative")
+		n.fail("retry times must be non-negative")
+		return n
 	}
 	n.retry = times
 	return n
@@ -135,7 +166,8 @@ func (n *node) SkipIf(skip bool) *node {
 
 func (n *node) WithTimeout(t time.Duration) *node {
 	if t <= 0 {
-		panic("timeout must be positive")
+		n.fail("timeout must be positive")
+		return n
 	}
 	n.timeout = t
 	return n
@@ -0,0 +1,60 @@
+package group
+
+import (
+	"context"
+	"time"
+)
+
+// run events observed during node executions
+type eventKind uint8
+
+const (
+	evReady     eventKind = iota // node is ready and waiting for a concurrency slot
+	evStart                      // node starts executing
+	evRetry                      // node attempt failed and will be retried
+	evTimeout                    // node timed out
+	evSkip                       // node skipped by condition
+	evEnd                        // node finished (with the final error)
+	evRollback                   // node rolled back (with the rollback error)
+	evCacheHit                   // node result restored from cache
+	evCacheMiss                  // node result not cached
+)
+
+type event struct {
+	kind    eventKind
+	n       *node
+	at      time.Time
+	attempt int   // attempt number (retry)
+	err     error // attempt error (retry), final error (end) or rollback error (rollback)
+}
+
+// observer receives run events, it must be safe for concurrent use
+type observer interface {
+	observe(e event)
+}
+
+type observerKey struct{}
+
+// withObserver returns a new context with o appended to the run observers
+func withObserver(ctx context.Context, o observer) context.Context {
+	obs, _ := ctx.Value(observerKey{}).([]observer)
+	return context.WithValue(ctx, observerKey{}, append(obs[:len(obs):len(obs)], o))
+}
+
+// observers is the set of run observers of a context
+type observers []observer
+
+func observersFrom(ctx context.Context) observers {
+	obs, _ := ctx.Value(observerKey{}).([]observer)
+	return obs
+}
+
+func (obs observers) notify(kind eventKind, n *node, attempt int, err error) {
+	if len(obs) == 0 {
+		return
+	}
+	e := event{kind: kind, n: n, at: time.Now(), attempt: attempt, err: err}
+	for _, o := range obs {
+		o.observe(e)
+	}
+}
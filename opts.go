@@ -2,6 +2,7 @@ package group
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"time"
 )
@@ -16,6 +17,18 @@ type Options struct {
 	after   AfterFunc     // group post-execution interceptor
 	timeout time.Duration // group timeout
 	log     bool          // enable logging with default or custom logger
+	pprof   bool          // run executions under pprof labels
+	strict  StrictMode    // dependency-scoped store views
+
+	joinErrs  bool // run all funcs and join their errors (func mode)
+	threshold int  // cancel the remaining funcs after threshold failures (join mode)
+	ordered   bool // yield stream results in input order
+
+	panicPolicy  PanicPolicy  // panic handling policy
+	panicHandler PanicHandler // custom panic handler
+
+	builder bool    // accumulate configuration errors instead of panicking
+	errs    []error // accumulated configuration errors (builder mode)
 
 	ErrC chan error // error collector
 }
@@ -32,24 +45,73 @@ func Opts(opts ...option) *Options {
 
 func WithPrefix(s string) option { return func(o *Options) { o.prefix = s } }
 func WithLimit(x int) option {
-	if x <= 0 {
-		panic("limit must be positive")
+	return func(o *Options) {
+		if x <= 0 {
+			o.fail("limit must be positive")
+			return
+		}
+		o.limit = x
 	}
-	return func(o *Options) { o.limit = x }
 }
 func WithPreFunc(f PreFunc) option     { return func(o *Options) { o.pre = f } }
 func WithAfterFunc(f AfterFunc) option { return func(o *Options) { o.after = f } }
 func WithTimeout(t time.Duration) option {
-	if t <= 0 {
-		panic("timeout must be positive")
+	return func(o *Options) {
+		if t <= 0 {
+			o.fail("timeout must be positive")
+			return
+		}
+		o.timeout = t
 	}
-	return func(o *Options) { o.timeout = t }
 }
 
 var WithLog option = func(o *Options) { o.log = true }
 
+var WithPprofLabels option = func(o *Options) { o.pprof = true }
+
 func WithLogger(logger *slog.Logger) option {
 	return func(o *Options) { o.log = true; slog.SetDefault(logger) }
 }
 
+// WithStrictStore scopes the storer-context of each node to keys written by its transitive upstreams (and explicit Put keys)
+func WithStrictStore(mode StrictMode) option { return func(o *Options) { o.strict = mode } }
+
+// WithJoinErrors runs all funcs to completion and joins their errors into *ResultsError, func mode only
+var WithJoinErrors option = func(o *Options) { o.joinErrs = true }
+
+// WithErrorThreshold joins errors as WithJoinErrors, but cancels the remaining funcs after n failures
+func WithErrorThreshold(n int) option {
+	return func(o *Options) {
+		if n <= 0 {
+			o.fail("error threshold must be positive")
+			return
+		}
+		o.joinErrs, o.threshold = true, n
+	}
+}
+
+// WithOrdered makes Map yield results in input order
+var WithOrdered option = func(o *Options) { o.ordered = true }
+
 func WithErrorCollector(errC chan error) option { return func(o *Options) { o.ErrC = errC } }
+
+func WithPanicPolicy(p PanicPolicy) option { return func(o *Options) { o.panicPolicy = p } }
+func WithPanicHandler(h PanicHandler) option {
+	return func(o *Options) { o.panicPolicy, o.panicHandler = PanicCustom, h }
+}
+
+// fail records a configuration error in builder mode, panics otherwise
+func (o *Options) fail(msg string) {
+	if !o.builder {
+		panic(msg)
+	}
+	o.errs = append(o.errs, fmt.Errorf("%w: %s", ErrInvalidGroup, msg))
+}
+
+// failErr records err in builder mode, panics with its message otherwise
+func (o *Options) failErr(err error) {
+	if !o.builder {
+		panic(err.Error())
+	}
+	o.errs = append(o.errs, err)
+}
@@ -0,0 +1,221 @@
+package group
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Ranger is a store that can iterate over its entries (implemented by built-in stores and *sync.Map)
+type Ranger interface {
+	Range(f func(key, value any) bool)
+}
+
+// storeEntry is a gob encoded store entry
+/*
+ * keys and values are encoded as interfaces, register custom types with gob.Register
+ */
+type storeEntry struct {
+	Key   any
+	Value any
+}
+
+// Snapshot writes all entries of store to w (gob encoded)
+func Snapshot(w io.Writer, store Ranger) error {
+	var entries []storeEntry
+	store.Range(func(key, value any) bool {
+		entries = append(entries, storeEntry{Key: key, Value: value})
+		return true
+	})
+	if err := gob.NewEncoder(w).Encode(entries); err != nil {
+		return fmt.Errorf("snapshot store: %w", err)
+	}
+	return nil
+}
+
+// Restore loads entries written by Snapshot from r into store
+func Restore(r io.Reader, store Storer) error {
+	var entries []storeEntry
+	if err := gob.NewDecoder(r).Decode(&entries); err != nil {
+		return fmt.Errorf("restore store: %w", err)
+	}
+	for _, e := range entries {
+		store.Store(e.Key, e.Value)
+	}
+	return nil
+}
+
+func (s *mapStore) Range(f func(key, value any) bool) {
+	for k, v := range *s.ptr.Load() {
+		if !f(k, v) {
+			return
+		}
+	}
+}
+
+func (s *shardedStore) Range(f func(key, value any) bool) {
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.RLock()
+		entries := make([]storeEntry, 0, len(sh.m))
+		for k, v := range sh.m {
+			entries = append(entries, storeEntry{Key: k, Value: v})
+		}
+		sh.mu.RUnlock()
+		for _, e := range entries {
+			if !f(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (s *syncMapStore) Range(f func(key, value any) bool) {
+	s.m.Range(f)
+}
+
+// file-backed append-only store, entries survive process restarts
+// [ideal for batch jobs and debugging]
+type fileStore struct {
+	mu   sync.RWMutex
+	m    map[any]any
+	file *os.File
+	enc  *gob.Encoder // encoder of the session, gob type definitions are written once per session
+	buf  bytes.Buffer // encoded record
+	err  error        // first write error
+}
+
+// NewFileStore opens (or creates) the store file and loads its entries
+/*
+ * every Store appends a gob encoded record to the file, the latest record of a key wins
+ * a torn trailing record (interrupted write) is truncated on load
+ * keys and values are encoded as interfaces, register custom types with gob.Register
+ * write errors are reported by Err and Close
+ */
+func NewFileStore(filename string) (*fileStore, error) {
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s := &fileStore{m: make(map[any]any), file: file}
+	if err := s.load(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("load store file %s: %w", filename, err)
+	}
+	return s, nil
+}
+
+// load replays length-prefixed records of the file
+/*
+ * an empty record starts an encoder session, records of a session share one decoder
+ * the file is truncated to the last complete record if the trailing one is torn
+ */
+func (s *fileStore) load() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	var (
+		r      = bufio.NewReader(s.file)
+		offset int64 // end of the last complete record
+		buf    bytes.Buffer
+		dec    = gob.NewDecoder(&buf)
+	)
+	for {
+		size, err := binary.ReadUvarint(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var record []byte
+		end := offset + int64(len(binary.AppendUvarint(nil, size))) + int64(size)
+		if err == nil && (end > info.Size() || end < offset) {
+			err = io.ErrUnexpectedEOF // beyond the end of the file
+		}
+		if err == nil {
+			record = make([]byte, size)
+			_, err = io.ReadFull(r, record)
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) { // torn trailing record
+			return s.file.Truncate(offset)
+		}
+		if err != nil {
+			return err
+		}
+		offset = end
+		if size == 0 { // new session
+			buf.Reset()
+			dec = gob.NewDecoder(&buf)
+			continue
+		}
+		buf.Write(record)
+		var e storeEntry
+		if err := dec.Decode(&e); err != nil {
+			return err
+		}
+		s.m[e.Key] = e.Value
+	}
+}
+
+func (s *fileStore) Load(key any) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[key]
+	return v, ok
+}
+
+func (s *fileStore) Store(key, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = value
+	var record []byte
+	if s.enc == nil {
+		s.enc = gob.NewEncoder(&s.buf)
+		record = binary.AppendUvarint(record, 0) // session start
+	}
+	s.buf.Reset()
+	err := s.enc.Encode(storeEntry{Key: key, Value: value})
+	if err == nil {
+		record = binary.AppendUvarint(record, uint64(s.buf.Len()))
+		_, err = s.file.Write(append(record, s.buf.Bytes()...))
+	}
+	if err != nil {
+		s.enc = nil // type definitions may be partially sent, restart the session
+		if s.err == nil {
+			s.err = fmt.Errorf("store %v: %w", key, err)
+		}
+	}
+}
+
+func (s *fileStore) Range(f func(key, value any) bool) {
+	s.mu.RLock()
+	entries := make([]storeEntry, 0, len(s.m))
+	for k, v := range s.m {
+		entries = append(entries, storeEntry{Key: k, Value: v})
+	}
+	s.mu.RUnlock()
+	for _, e := range entries {
+		if !f(e.Key, e.Value) {
+			return
+		}
+	}
+}
+
+// Err returns the first write error
+func (s *fileStore) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.err
+}
+
+// Close syncs and closes the store file, returns the first write error if any
+func (s *fileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Join(s.err, s.file.Sync(), s.file.Close())
+}
@@ -0,0 +1,108 @@
+package group
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/oatcatx/group/internal/pprofproto"
+)
+
+// pprof label keys
+const (
+	LabelGroup = "group"
+	LabelNode  = "node"
+	LabelFunc  = "func"
+)
+
+type profileKey struct{}
+
+// profiling reports whether executions should run under pprof labels
+func (o *Options) profiling(ctx context.Context) bool {
+	return o.pprof || ctx.Value(profileKey{}) != nil
+}
+
+// doLabeled runs f under pprof labels
+func doLabeled(ctx context.Context, f func(context.Context) error, labels ...string) (err error) {
+	pprof.Do(ctx, pprof.Labels(labels...), func(ctx context.Context) {
+		err = f(ctx)
+	})
+	return
+}
+
+// RunProfile is the CPU profile of a single group run summarized by node label
+type RunProfile struct {
+	Total time.Duration            // total sampled cpu time during the run
+	Nodes map[string]time.Duration // sampled cpu time per node label
+	Raw   []byte                   // raw gzipped cpu profile in pprof format, readable by go tool pprof
+	Err   error                    // error returned by the group run
+}
+
+// Profile runs the group once under a CPU profile and summarizes cpu time per node label
+/*
+ * node executions are labeled during the profiled run even if WithPprofLabels is not set
+ * only one CPU profile can be active per process, an error is returned if profiling is already enabled
+ * the group run error is reported in RunProfile.Err
+ */
+func (g *Group) Profile(ctx context.Context, shared ...any) (*RunProfile, error) {
+	var buf bytes.Buffer
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		return nil, fmt.Errorf("failed to start cpu profile: %w", err)
+	}
+	runErr := g.Go(context.WithValue(ctx, profileKey{}, struct{}{}), shared...)
+	pprof.StopCPUProfile()
+
+	rp := &RunProfile{Nodes: make(map[string]time.Duration), Raw: buf.Bytes(), Err: runErr}
+	p, err := pprofproto.Parse(rp.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cpu profile: %w", err)
+	}
+	idx := slices.Index(p.Units, "nanoseconds")
+	if idx < 0 {
+		return rp, nil
+	}
+	for _, s := range p.Samples {
+		if idx >= len(s.Values) {
+			continue
+		}
+		d := time.Duration(s.Values[idx])
+		rp.Total += d
+		if !slices.Contains(s.Labels[LabelGroup], g.prefix) {
+			continue
+		}
+		for _, node := range s.Labels[LabelNode] {
+			rp.Nodes[node] += d
+		}
+	}
+	return rp, nil
+}
+
+// String formats the node summary sorted by cpu time
+func (rp *RunProfile) String() string {
+	type entry struct {
+		node string
+		d    time.Duration
+	}
+	entries := make([]entry, 0, len(rp.Nodes))
+	for node, d := range rp.Nodes {
+		entries = append(entries, entry{node, d})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return cmp.Or(cmp.Compare(b.d, a.d), strings.Compare(a.node, b.node))
+	})
+	var b strings.Builder
+	fmt.Fprintf(&b, "total %s\n", rp.Total)
+	for _, e := range entries {
+		var pct float64
+		if rp.Total > 0 {
+			pct = float64(e.d) / float64(rp.Total) * 100
+		}
+		fmt.Fprintf(&b, "%-24s %12s %6.2f%%\n", e.node, e.d, pct)
+	}
+	return b.String()
+}
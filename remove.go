@@ -0,0 +1,112 @@
+package group
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RemovePolicy decides what happens to dependents of a removed node
+type RemovePolicy uint8
+
+const (
+	RemoveError    RemovePolicy = iota // fail if the node has dependents
+	RemoveDetach                       // dependents lose the dependency on the removed node
+	RemoveReattach                     // dependents inherit the dependencies of the removed node (weak if either edge is weak)
+	RemoveCascade                      // dependents are removed transitively
+)
+
+// Remove removes the node by key, dependents are handled by policy (defaults to RemoveError)
+/*
+ * missing keys and RemoveError with dependents PANIC (or are accumulated in builder mode)
+ * forward dependencies are resolved first, so dependents declared before the removed node are handled too
+ */
+func (g *Group) Remove(key any, policy ...RemovePolicy) *Group {
+	_ = g.resolve() // edges are linked before removing
+	idx, ok := g.idxMap[key]
+	if !ok {
+		g.fail(fmt.Sprintf("missing node %q", key))
+		return g
+	}
+	p := RemoveError
+	if len(policy) > 0 {
+		p = policy[0]
+	}
+	switch n := g.nodes[idx]; p {
+	case RemoveError:
+		if len(n.to) > 0 {
+			var dependents []string
+			for _, toIdx := range n.to {
+				dependents = append(dependents, fmt.Sprintf("%q", nodeName(g.nodes[toIdx])))
+			}
+			g.fail(fmt.Sprintf("node %q has dependents %s", key, strings.Join(dependents, ", ")))
+			return g
+		}
+	case RemoveReattach:
+		for _, toIdx := range n.to {
+			to, weak := g.nodes[toIdx], slices.Contains(n.weakTo, toIdx)
+			for _, depIdx := range n.deps {
+				if depIdx == toIdx || slices.Contains(to.deps, depIdx) {
+					continue
+				}
+				to.link(depIdx, weak || slices.Contains(g.nodes[depIdx].weakTo, idx))
+			}
+		}
+	case RemoveCascade:
+		removed := []int{idx}
+		for i := 0; i < len(removed); i++ {
+			for _, toIdx := range g.nodes[removed[i]].to {
+				if !slices.Contains(removed, toIdx) {
+					removed = append(removed, toIdx)
+				}
+			}
+		}
+		// remove from the back so that indices to remove stay valid
+		slices.Sort(removed)
+		for _, i := range slices.Backward(removed) {
+			g.removeAt(i)
+		}
+		return g
+	}
+	g.removeAt(idx)
+	return g
+}
+
+// Replace replaces the func of the node by key, f is any func type accepted by [Add...] methods or a Node / AutoNode
+func (g *Group) Replace(key any, f any) *Group {
+	n := g.Node(key)
+	if n == nil {
+		g.fail(fmt.Sprintf("missing node %q", key))
+		return g
+	}
+	n.WithFunc(f)
+	return g
+}
+
+// removeAt removes the node at idx and reindexes nodes, edges, keys and pending dependencies
+func (g *Group) removeAt(idx int) {
+	reindex := func(indices []int) []int {
+		indices = slices.DeleteFunc(indices, func(i int) bool { return i == idx })
+		for j, i := range indices {
+			if i > idx {
+				indices[j] = i - 1
+			}
+		}
+		return indices
+	}
+	g.nodes = slices.Delete(g.nodes, idx, idx+1)
+	g.x = len(g.nodes)
+	clear(g.idxMap)
+	for i, n := range g.nodes {
+		n.idx, n.deps, n.to, n.weakTo = i, reindex(n.deps), reindex(n.to), reindex(n.weakTo)
+		if n.key != nil {
+			g.idxMap[n.key] = i
+		}
+	}
+	g.pending = slices.DeleteFunc(g.pending, func(p pendingDep) bool { return p.idx == idx })
+	for j, p := range g.pending {
+		if p.idx > idx {
+			g.pending[j].idx = p.idx - 1
+		}
+	}
+}
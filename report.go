@@ -0,0 +1,158 @@
+package group
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// NodeStatus is the outcome of a node in a group run
+type NodeStatus uint8
+
+const (
+	StatusBlocked    NodeStatus = iota // node never started (upstream failed or group halted)
+	StatusSucceeded                    // node succeeded
+	StatusFailed                       // node failed
+	StatusSkipped                      // node skipped by condition
+	StatusTimedOut                     // node timed out
+	StatusRolledBack                   // node rolled back
+)
+
+func (s NodeStatus) String() string {
+	switch s {
+	case StatusSucceeded:
+		return "succeeded"
+	case StatusFailed:
+		return "failed"
+	case StatusSkipped:
+		return "skipped"
+	case StatusTimedOut:
+		return "timed out"
+	case StatusRolledBack:
+		return "rolled back"
+	default:
+		return "blocked"
+	}
+}
+
+// NodeResult is the result of a node in a group run
+type NodeResult struct {
+	Key      any
+	Status   NodeStatus
+	Duration time.Duration
+	Attempts int
+	Cached   bool  // restored from cache
+	Err      error // node error (or rollback error if rollback failed)
+}
+
+// Report collects node results of group runs
+/*
+ * attach it to the run context using WithReport, results are available after the run
+ * pass it to GraphOptions.Report to overlay the results onto the rendered graph
+ * a report records a single run per group, use a new report for each run
+ */
+type Report struct {
+	mu      sync.Mutex
+	groups  []*Group
+	results map[*node]*nodeResult
+}
+
+type nodeResult struct {
+	NodeResult
+	start    time.Time
+	timedOut bool
+}
+
+func NewReport() *Report {
+	return &Report{results: make(map[*node]*nodeResult)}
+}
+
+// WithReport returns a new context reporting group runs into r
+func WithReport(ctx context.Context, r *Report) context.Context {
+	return withObserver(ctx, r)
+}
+
+func (r *Report) observe(e event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := r.results[e.n]
+	if res == nil {
+		res = &nodeResult{NodeResult: NodeResult{Key: e.n.key}}
+		r.results[e.n] = res
+		if !slices.Contains(r.groups, e.n.Group) {
+			r.groups = append(r.groups, e.n.Group)
+		}
+	}
+	switch e.kind {
+	case evStart:
+		res.start, res.Attempts = e.at, 1
+	case evRetry:
+		res.Attempts = e.attempt + 1
+	case evTimeout:
+		res.timedOut = true
+	case evSkip:
+		res.Status = StatusSkipped
+	case evCacheHit:
+		res.Cached = true
+	case evEnd:
+		res.Duration, res.Err = e.at.Sub(res.start), e.err
+		switch {
+		case res.timedOut:
+			res.Status = StatusTimedOut
+		case e.err != nil:
+			res.Status = StatusFailed
+		case res.Status != StatusSkipped:
+			res.Status = StatusSucceeded
+		}
+	case evRollback:
+		res.Status = StatusRolledBack
+		if e.err != nil {
+			res.Err = e.err
+		}
+	}
+}
+
+// result of node n, nodes of a reported group that never started are blocked
+func (r *Report) result(n *node) (NodeResult, bool) {
+	if r == nil {
+		return NodeResult{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if res := r.results[n]; res != nil {
+		return res.NodeResult, true
+	}
+	if slices.Contains(r.groups, n.Group) {
+		return NodeResult{Key: n.key, Status: StatusBlocked}, true
+	}
+	return NodeResult{}, false
+}
+
+// Result returns the result of the node with the given key
+func (r *Report) Result(key any) (NodeResult, bool) {
+	r.mu.Lock()
+	groups := r.groups
+	r.mu.Unlock()
+	for _, g := range groups {
+		if n := g.Node(key); n != nil {
+			return r.result(n)
+		}
+	}
+	return NodeResult{}, false
+}
+
+// Results returns results of all nodes of reported groups in adding order
+func (r *Report) Results() []NodeResult {
+	r.mu.Lock()
+	groups := r.groups
+	r.mu.Unlock()
+	var results []NodeResult
+	for _, g := range groups {
+		for _, n := range g.nodes {
+			res, _ := r.result(n)
+			results = append(results, res)
+		}
+	}
+	return results
+}
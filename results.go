@@ -0,0 +1,108 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrNotRun = errors.New("func not run")
+
+// ResultsError is the error of GoResults and of join mode (WithJoinErrors / WithErrorThreshold)
+/*
+ * Errs holds the error of each func by index, nil for succeeded funcs,
+ * ErrNotRun for funcs never run since the group failed first,
+ * the group error for funcs still running when the group timed out
+ */
+type ResultsError struct {
+	Errs []error
+	err  error // group error
+}
+
+func (e *ResultsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ResultsError) Unwrap() error {
+	return e.err
+}
+
+// Failed returns the indexes of failed (or not run) funcs
+func (e *ResultsError) Failed() []int {
+	var idxs []int
+	for i, err := range e.Errs {
+		if err != nil {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs
+}
+
+// GoResults executes fs as GoCtx does and collects their results in input order
+/*
+ * on failure, results of succeeded funcs are kept (zero values otherwise),
+ * and the error is a *ResultsError holding the error of each func by index
+ */
+func GoResults[T any](ctx context.Context, opts *Options, fs ...func(context.Context) (T, error)) ([]T, error) {
+	var (
+		mu      sync.Mutex
+		closed  bool // funcs still running after a group timeout must not write
+		results = make([]T, len(fs))
+		errs    = make([]error, len(fs))
+		state   = make([]funcState, len(fs))
+	)
+	gfs := make([]groupFunc, len(fs))
+	for i, f := range fs {
+		gfs[i] = groupFunc{fn: f, f: func(ctx context.Context) error {
+			mu.Lock()
+			state[i] = funcRunning
+			mu.Unlock()
+			var v T
+			run := func() (err error) {
+				v, err = f(ctx)
+				return
+			}
+			var err error
+			if opts == nil { // recover here to record the panic of this func only
+				err = SafeRun(ctx, run)
+			} else {
+				err = opts.safeRun(ctx, run)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !closed {
+				if state[i] = funcDone; err != nil {
+					errs[i] = fmt.Errorf("func #%d: %w", i, err)
+				} else {
+					results[i] = v
+				}
+			}
+			return err
+		}}
+	}
+	err := goFuncs(ctx, opts, gfs)
+	mu.Lock()
+	defer mu.Unlock()
+	closed = true
+	if err == nil {
+		return results, nil
+	}
+	for i := range errs {
+		switch state[i] {
+		case funcPending:
+			errs[i] = ErrNotRun
+		case funcRunning: // timed out
+			errs[i] = fmt.Errorf("func #%d: %w", i, err)
+		}
+	}
+	return results, &ResultsError{Errs: errs, err: err}
+}
+
+type funcState uint8
+
+const (
+	funcPending funcState = iota
+	funcRunning
+	funcDone
+)
@@ -6,62 +6,101 @@ import (
 	"fmt"
 	"log/slog"
 	"runtime"
+	"runtime/debug"
 	"strconv"
 )
 
 var ErrPanic = errors.New("panic recovered")
 
-const bufSize int = 64 << 10
+// PanicError is a recovered panic carrying the panic value, stack and location
+/*
+ * errors.Is(err, ErrPanic) reports true for any *PanicError
+ * if the panic value is an error, it can be unwrapped as well
+ */
+type PanicError struct {
+	Value any    // recovered panic value
+	Stack []byte // stack of the panicking goroutine
+	File  string // panic location file
+	Line  int    // panic location line
+	Func  string // panic location func
+}
+
+func (e *PanicError) Error() string {
+	if loc := e.Location(); loc != "" {
+		return fmt.Sprintf("%v at %s: %v", ErrPanic, loc, e.Value)
+	}
+	return fmt.Sprintf("%v: %v", ErrPanic, e.Value)
+}
+
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrPanic, err}
+	}
+	return []error{ErrPanic}
+}
+
+// Location returns the panic location formatted as "file:line (func)"
+func (e *PanicError) Location() string {
+	if e.File == "" {
+		return ""
+	}
+	loc := e.File + ":" + strconv.Itoa(e.Line)
+	if e.Func != "" {
+		loc += " (" + e.Func + ")"
+	}
+	return loc
+}
+
+// PanicPolicy decides how panics are handled within group nodes, interceptors, conditions and rollbacks
+type PanicPolicy uint8
+
+const (
+	PanicAsError  PanicPolicy = iota // recover panic as *PanicError (default)
+	PanicFastFail                    // recover panic as *PanicError and halt the group
+	PanicRepanic                     // halt the group, run cleanup (rollback & post-execution interceptor), then re-panic
+	PanicCustom                      // recover panic and let the PanicHandler decide the error
+)
+
+// PanicHandler converts a recovered panic into the error of the panicking func (nil to swallow it)
+type PanicHandler func(ctx context.Context, p *PanicError) error
+
+// newPanicError builds a *PanicError for r, skip is the caller depth of the panic location
+func newPanicError(r any, skip int) *PanicError {
+	p := &PanicError{Value: r, Stack: debug.Stack()}
+	if pc, file, line, ok := runtime.Caller(skip); ok {
+		p.File, p.Line = file, line
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			p.Func = fn.Name()
+		}
+	}
+	return p
+}
+
+func logPanic(ctx context.Context, p *PanicError) {
+	panicAttrs := make([]slog.Attr, 0, 3)
+	panicAttrs = append(panicAttrs, slog.String("type", fmt.Sprintf("%T", p.Value)), slog.Any("value", p.Value))
+	if p.File != "" {
+		locAttrs := make([]slog.Attr, 0, 3)
+		locAttrs = append(locAttrs, slog.String("file", p.File), slog.Int("line", p.Line))
+		if p.Func != "" {
+			locAttrs = append(locAttrs, slog.String("func", p.Func))
+		}
+		panicAttrs = append(panicAttrs, slog.GroupAttrs("location", locAttrs...))
+	}
+	slog.LogAttrs(ctx, slog.LevelError, ErrPanic.Error(), slog.GroupAttrs("panic", panicAttrs...), slog.String("stack", string(p.Stack)))
+}
 
 func RecoverCtx(ctx context.Context) {
 	if r := recover(); r != nil {
-		panicAttrs, locAttrs := make([]slog.Attr, 0, 3), make([]slog.Attr, 0, 3)
-		panicAttrs = append(panicAttrs, slog.String("type", fmt.Sprintf("%T", r)), slog.Any("value", r))
-		var loc string
-		if pc, file, line, ok := runtime.Caller(2); ok {
-			loc = file + ":" + strconv.Itoa(line)
-			locAttrs = append(locAttrs, slog.String("file", file), slog.Int("line", line))
-			if fn := runtime.FuncForPC(pc); fn != nil {
-				fnName := fn.Name()
-				loc += " (" + fnName + ")"
-				locAttrs = append(locAttrs, slog.String("func", fnName))
-			}
-			panicAttrs = append(panicAttrs, slog.GroupAttrs("location", locAttrs...))
-		}
-		buf := make([]byte, bufSize)
-		slog.LogAttrs(ctx, slog.LevelError, ErrPanic.Error(), slog.GroupAttrs("panic", panicAttrs...), slog.String("stack", string(buf[:runtime.Stack(buf, false)])))
+		logPanic(ctx, newPanicError(r, 3))
 	}
 }
 
 func RecoverCtxErr(ctx context.Context, err *error) {
 	if r := recover(); r != nil {
-		panicAttrs, locAttrs := make([]slog.Attr, 0, 3), make([]slog.Attr, 0, 3)
-		panicAttrs = append(panicAttrs, slog.String("type", fmt.Sprintf("%T", r)), slog.Any("value", r))
-		var loc string
-		if pc, file, line, ok := runtime.Caller(2); ok {
-			loc = file + ":" + strconv.Itoa(line)
-			locAttrs = append(locAttrs, slog.String("file", file), slog.Int("line", line))
-			if fn := runtime.FuncForPC(pc); fn != nil {
-				fnName := fn.Name()
-				loc += " (" + fnName + ")"
-				locAttrs = append(locAttrs, slog.String("func", fnName))
-			}
-			panicAttrs = append(panicAttrs, slog.GroupAttrs("location", locAttrs...))
-		}
-		buf := make([]byte, bufSize)
-		slog.LogAttrs(ctx, slog.LevelError, ErrPanic.Error(), slog.GroupAttrs("panic", panicAttrs...), slog.String("stack", string(buf[:runtime.Stack(buf, false)])))
-
-		var panicErr error
-		if e, ok := r.(error); ok {
-			panicErr = e
-		} else {
-			panicErr = fmt.Errorf("%v", r)
-		}
-		if loc != "" {
-			*err = fmt.Errorf("%w at %s: %w", ErrPanic, loc, panicErr)
-		} else {
-			*err = fmt.Errorf("%w: %w", ErrPanic, panicErr)
-		}
+		p := newPanicError(r, 3)
+		logPanic(ctx, p)
+		*err = p
 	}
 }
 
@@ -74,3 +113,49 @@ func SafeRunNode(ctx context.Context, f func(context.Context, any) error, shared
 	defer RecoverCtxErr(ctx, &err)
 	return f(ctx, shared)
 }
+
+// safeRun runs f under the panic policy of the options
+func (o *Options) safeRun(ctx context.Context, f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = o.handlePanic(ctx, newPanicError(r, 3))
+		}
+	}()
+	return f()
+}
+
+func (o *Options) safeRunNode(ctx context.Context, f func(context.Context, any) error, shared any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = o.handlePanic(ctx, newPanicError(r, 3))
+		}
+	}()
+	return f(ctx, shared)
+}
+
+func (o *Options) handlePanic(ctx context.Context, p *PanicError) error {
+	if o.panicPolicy == PanicCustom && o.panicHandler != nil {
+		return o.panicHandler(ctx, p)
+	}
+	logPanic(ctx, p)
+	return p
+}
+
+// haltOnPanic reports the recovered panic in err if the panic policy halts the group
+func (o *Options) haltOnPanic(err error) (*PanicError, bool) {
+	if err == nil || o.panicPolicy != PanicFastFail && o.panicPolicy != PanicRepanic {
+		return nil, false
+	}
+	var p *PanicError
+	if errors.As(err, &p) {
+		return p, true
+	}
+	return nil, false
+}
+
+// repanic re-panics the recovered panic in err under PanicRepanic policy
+func (o *Options) repanic(err error) {
+	if p, ok := o.haltOnPanic(err); ok && o.panicPolicy == PanicRepanic {
+		panic(p)
+	}
+}
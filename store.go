@@ -2,8 +2,11 @@ package group
 
 import (
 	"context"
+	"hash/maphash"
 	"maps"
+	"sync"
 	"sync/atomic"
+	"unsafe"
 )
 
 // WithStore returns a new context with the provided store (storer-context)
@@ -89,3 +92,70 @@ func (s *mapStore) Store(key, value any) {
 		}
 	}
 }
+
+// sharded mutex-protected map store, keys must be comparable
+// [ideal for write-heavy scenarios]
+type shardedStore struct {
+	seed   maphash.Seed
+	shards []storeShard
+}
+
+type storeShard struct {
+	mu sync.RWMutex
+	m  map[any]any
+	// pads shards to a 64-byte cache line against false sharing
+	_ [64 - (unsafe.Sizeof(sync.RWMutex{})+unsafe.Sizeof(map[any]any(nil)))%64]byte
+}
+
+// NewShardedStore returns a sharded store, shards are rounded up to a power of two (defaults to 32 if non-positive)
+func NewShardedStore(shards int) *shardedStore {
+	if shards <= 0 {
+		shards = 32
+	}
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+	s := &shardedStore{seed: maphash.MakeSeed(), shards: make([]storeShard, n)}
+	for i := range s.shards {
+		s.shards[i].m = make(map[any]any)
+	}
+	return s
+}
+
+func (s *shardedStore) shard(key any) *storeShard {
+	return &s.shards[maphash.Comparable(s.seed, key)&uint64(len(s.shards)-1)]
+}
+
+func (s *shardedStore) Load(key any) (any, bool) {
+	sh := s.shard(key)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	v, ok := sh.m[key]
+	return v, ok
+}
+
+func (s *shardedStore) Store(key, value any) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.m[key] = value
+}
+
+// sync.Map backed store
+// [ideal for write-once-read-many keys and disjoint writers]
+type syncMapStore struct {
+	m sync.Map
+}
+
+func NewSyncMapStore() *syncMapStore {
+	return &syncMapStore{}
+}
+
+func (s *syncMapStore) Load(key any) (any, bool) {
+	return s.m.Load(key)
+}
+
+func (s *syncMapStore) Store(key, value any) {
+	s.m.Store(key, value)
+}
@@ -0,0 +1,277 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// streamLimit returns the concurrency limit of streams, GOMAXPROCS by default
+func (o *Options) streamLimit() int {
+	if o.limit > 0 {
+		return o.limit
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// ForEach runs f on each input of seq concurrently as Go does
+/*
+ * inputs are pulled lazily, at most limit funcs (WithLimit, GOMAXPROCS by default) run at once
+ * and pulling blocks until one finishes (backpressure)
+ * the first error cancels the remaining inputs, unless in join mode (WithJoinErrors / WithErrorThreshold)
+ * seq is never pulled after ForEach returns, a seq blocking between inputs delays the return on timeout
+ */
+func ForEach[T any](ctx context.Context, opts *Options, seq iter.Seq[T], f func(context.Context, T) error) (err error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.prefix == "" {
+		opts.prefix = "anonymous" // default prefix
+	}
+
+	if opts.log {
+		defer func(start time.Time) {
+			groupMonitor(ctx, "ForEach", opts.prefix, start, opts.log, err)
+		}(time.Now())
+	}
+
+	g, ctx, j := opts.errgroup(ctx, 0)
+	defer j.close()
+
+	// group timeout
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	// group pre-execution interceptor
+	if opts.pre != nil {
+		if err := opts.safeRun(ctx, func() error { return opts.pre(ctx) }); err != nil {
+			opts.repanic(err)
+			return err
+		}
+	}
+	defer func() {
+		p, _ := opts.haltOnPanic(err)
+		// group post-execution interceptor
+		if opts.after != nil {
+			e := err
+			if err = opts.safeRun(ctx, func() error { return opts.after(ctx, e) }); p == nil {
+				p, _ = opts.haltOnPanic(err)
+			}
+		}
+		// re-panic after cleanup
+		if p != nil && opts.panicPolicy == PanicRepanic {
+			panic(p)
+		}
+	}()
+
+	sem := make(chan struct{}, opts.streamLimit())
+	pulled := make(chan struct{}) // closed once the producer stops pulling seq
+	done := make(chan error, 1)
+	go func() {
+		i := 0
+		for v := range seq {
+			select {
+			case sem <- struct{}{}: // blocks at limit
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			exec := execFunc(ctx, "[ForEach -> exec]", opts, j, i, func(ctx context.Context) error { return f(ctx, v) }, f)
+			g.Go(func() error {
+				defer func() { <-sem }()
+				return exec()
+			})
+			i++
+		}
+		close(pulled)
+		j.setLen(i)
+		done <- j.wait(ctx, g.Wait())
+	}()
+
+	select {
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) { // actual timeout
+			<-pulled // running funcs are not waited for, but seq is never pulled after returning
+			return opts.timeoutErr(ctx, "ForEach")
+		}
+		return <-done
+	case err = <-done:
+		return
+	}
+}
+
+// Map runs f on each input of seq concurrently, and yields the results with their errors
+/*
+ * inputs are pulled lazily once the results are iterated, at most limit funcs (WithLimit, GOMAXPROCS by default)
+ * run at once and at most limit results are buffered, pulling blocks until the results are consumed (backpressure)
+ * results are yielded as they finish, or in input order by using WithOrdered
+ * errors do not stop the stream, stop consuming to cancel the remaining inputs
+ * a group timeout or cancellation is yielded as the last error
+ * seq is never pulled after the iteration returns, a seq blocking between inputs delays the return
+ */
+func Map[T, R any](ctx context.Context, opts *Options, seq iter.Seq[T], f func(context.Context, T) (R, error)) iter.Seq2[R, error] {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.prefix == "" {
+		opts.prefix = "anonymous" // default prefix
+	}
+	return func(yield func(R, error) bool) {
+		var zero R
+		var err error          // first error, passed to the post-execution interceptor
+		var halted *PanicError // halting panic, which may not be the first error
+		if opts.log {
+			defer func(start time.Time) {
+				groupMonitor(ctx, "Map", opts.prefix, start, opts.log, err)
+			}(time.Now())
+		}
+
+		ctx, cancel := context.WithCancel(ctx) // cancels the remaining inputs when the consumer stops
+		defer cancel()
+
+		// group timeout
+		if opts.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+			defer cancel()
+		}
+
+		// group pre-execution interceptor
+		if opts.pre != nil {
+			if err = opts.safeRun(ctx, func() error { return opts.pre(ctx) }); err != nil {
+				opts.repanic(err)
+				yield(zero, err)
+				return
+			}
+		}
+		stopped := false
+		defer func() {
+			p := halted
+			// group post-execution interceptor
+			if opts.after != nil {
+				e := err
+				if err = opts.safeRun(ctx, func() error { return opts.after(ctx, e) }); p == nil {
+					p, _ = opts.haltOnPanic(err)
+				}
+				if err != nil && err != e && !stopped {
+					yield(zero, err)
+				}
+			}
+			// re-panic after cleanup
+			if p != nil && opts.panicPolicy == PanicRepanic {
+				panic(p)
+			}
+		}()
+
+		type result struct {
+			v   R
+			err error
+		}
+		limit := opts.streamLimit()
+		sem := make(chan struct{}, limit)
+		slots := make(chan chan result, limit) // result slots in input order (WithOrdered)
+		results := make(chan result, limit)    // results as they finish
+		pulled := make(chan struct{})          // closed once the producer stops pulling seq
+		defer func() {
+			cancel()
+			<-pulled // seq is never pulled after returning
+		}()
+		go func() {
+			var wg sync.WaitGroup
+			defer func() {
+				wg.Wait()
+				close(slots)
+				close(results)
+			}()
+			defer close(pulled)
+			i := 0
+			for v := range seq {
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+				}
+				if ctx.Err() != nil {
+					return
+				}
+				slot := make(chan result, 1)
+				if opts.ordered {
+					select {
+					case slots <- slot:
+					case <-ctx.Done():
+						return
+					}
+				}
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					var r result
+					r.err = execFunc(ctx, "[Map -> exec]", opts, nil, i, func(ctx context.Context) (err error) {
+						r.v, err = f(ctx, v)
+						return
+					}, f)()
+					if opts.ordered {
+						slot <- r
+					} else {
+						select {
+						case results <- r:
+						case <-ctx.Done():
+						}
+					}
+					<-sem
+				}(i)
+				i++
+			}
+		}()
+
+		for {
+			var r result
+			ok := false
+			if opts.ordered {
+				var slot chan result
+				select {
+				case slot, ok = <-slots:
+				case <-ctx.Done():
+				}
+				if ok {
+					select {
+					case r = <-slot:
+					case <-ctx.Done():
+						ok = false
+					}
+				}
+			} else {
+				select {
+				case r, ok = <-results:
+				case <-ctx.Done():
+				}
+			}
+			if !ok {
+				if ctx.Err() == nil {
+					return // all inputs done
+				}
+				if err = ctx.Err(); errors.Is(err, context.DeadlineExceeded) { // actual timeout
+					err = opts.timeoutErr(ctx, "Map")
+				}
+				stopped = !yield(zero, err)
+				return
+			}
+			if r.err != nil && err == nil {
+				err = r.err
+			}
+			if stopped = !yield(r.v, r.err); stopped {
+				return
+			}
+			if p, halt := opts.haltOnPanic(r.err); halt {
+				halted = p
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package group
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrUndeclaredRead = errors.New("undeclared store read")
+
+// StrictMode is the store access mode of group runs
+type StrictMode uint8
+
+const (
+	StrictOff   StrictMode = iota // nodes read any key of the store
+	StrictError                   // undeclared reads miss and fail the node with ErrUndeclaredRead
+	StrictPanic                   // undeclared reads panic with ErrUndeclaredRead (handled by the panic policy, e.g. PanicRepanic in tests)
+)
+
+// strictRun tracks store writers of a group run
+type strictRun struct {
+	g         *Group
+	mode      StrictMode
+	store     Storer
+	ancestors [][]bool // ancestors[i][j]: node j is a transitive upstream of node i
+
+	mu      sync.RWMutex
+	writers map[any]*node
+}
+
+func newStrictRun(g *Group, mode StrictMode, store Storer) *strictRun {
+	r := &strictRun{g: g, mode: mode, store: store, ancestors: make([][]bool, len(g.nodes)), writers: make(map[any]*node)}
+	var visit func(idx int) []bool
+	visit = func(idx int) []bool {
+		if r.ancestors[idx] != nil {
+			return r.ancestors[idx]
+		}
+		r.ancestors[idx] = make([]bool, len(g.nodes)) // guards against cycles
+		for _, depIdx := range g.nodes[idx].deps {
+			r.ancestors[idx][depIdx] = true
+			for j, ok := range visit(depIdx) {
+				r.ancestors[idx][j] = r.ancestors[idx][j] || ok
+			}
+		}
+		return r.ancestors[idx]
+	}
+	for idx := range g.nodes {
+		visit(idx)
+	}
+	return r
+}
+
+// check reports an undeclared read of key by n
+/*
+ * keys of nodes other than n and its transitive upstreams are undeclared (even if not written yet),
+ * so are keys Put by such nodes, keys written outside the run are always readable
+ */
+func (r *strictRun) check(n *node, key any) error {
+	if idx, ok := r.g.idxMap[key]; ok && idx != n.idx && !r.ancestors[n.idx][idx] {
+		return fmt.Errorf("%w: node %q reads %q of non-upstream node", ErrUndeclaredRead, nodeName(n), fmt.Sprint(key))
+	}
+	r.mu.RLock()
+	writer, ok := r.writers[key]
+	r.mu.RUnlock()
+	if ok && writer != n && !r.ancestors[n.idx][writer.idx] {
+		return fmt.Errorf("%w: node %q reads %q written by non-upstream node %q", ErrUndeclaredRead, nodeName(n), fmt.Sprint(key), nodeName(writer))
+	}
+	return nil
+}
+
+// view returns the store view of node n
+func (r *strictRun) view(n *node) *strictView {
+	return &strictView{run: r, n: n}
+}
+
+// strictView is the dependency-scoped store view of a node
+type strictView struct {
+	run *strictRun
+	n   *node
+
+	mu  sync.Mutex
+	err error // first undeclared read (StrictError)
+}
+
+func (v *strictView) Load(key any) (any, bool) {
+	if err := v.run.check(v.n, key); err != nil {
+		if v.run.mode == StrictPanic {
+			panic(err)
+		}
+		v.mu.Lock()
+		if v.err == nil {
+			v.err = err
+		}
+		v.mu.Unlock()
+		return nil, false
+	}
+	return v.run.store.Load(key)
+}
+
+func (v *strictView) Store(key, value any) {
+	v.run.mu.Lock()
+	v.run.writers[key] = v.n
+	v.run.mu.Unlock()
+	v.run.store.Store(key, value)
+}
+
+// Err returns the first undeclared read
+func (v *strictView) Err() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.err
+}
@@ -0,0 +1,208 @@
+package group
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Trace records the timeline of group runs in Chrome Trace Event format
+/*
+ * attach it to the run context using WithTrace, then write it by WriteTo after the run
+ * the output is viewable in Perfetto (ui.perfetto.dev) or chrome://tracing
+ * - one process per group, one track per worker slot
+ * - node executions are slices on the slot track, retries, timeouts and cache hits / misses are instant events
+ * - waits on the concurrency limit are async slices
+ * - dependency edges are flow arrows from upstream end to downstream start
+ * a trace records a single run per group, use a new trace for each run
+ */
+type Trace struct {
+	mu     sync.Mutex
+	start  time.Time
+	groups []*traceGroup
+}
+
+type traceGroup struct {
+	g       *Group
+	slots   []bool // busy worker slots
+	spans   map[*node]*traceSpan
+	instant []traceEvent
+}
+
+type traceSpan struct {
+	n                 *node
+	deps              []*node
+	ready, start, end time.Time
+	slot              int
+	err               error
+}
+
+// traceEvent is a Chrome Trace Event
+type traceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   float64        `json:"ts"`
+	Dur  float64        `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	ID   int            `json:"id,omitempty"`
+	S    string         `json:"s,omitempty"`  // instant event scope
+	Bp   string         `json:"bp,omitempty"` // flow event binding point
+	Args map[string]any `json:"args,omitempty"`
+}
+
+func NewTrace() *Trace {
+	return &Trace{start: time.Now()}
+}
+
+// WithTrace returns a new context recording group runs into t
+func WithTrace(ctx context.Context, t *Trace) context.Context {
+	return withObserver(ctx, t)
+}
+
+func (t *Trace) observe(e event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tg := t.group(e.n.Group)
+	span := tg.spans[e.n]
+	if span == nil {
+		span = &traceSpan{n: e.n, slot: -1}
+		tg.spans[e.n] = span
+	}
+	switch e.kind {
+	case evReady:
+		span.ready = e.at
+	case evStart:
+		span.start = e.at
+		span.deps = make([]*node, 0, len(e.n.deps))
+		for _, depIdx := range e.n.deps {
+			span.deps = append(span.deps, e.n.nodes[depIdx])
+		}
+		// acquire the lowest free slot
+		span.slot = slices.Index(tg.slots, false)
+		if span.slot < 0 {
+			span.slot = len(tg.slots)
+			tg.slots = append(tg.slots, true)
+		}
+		tg.slots[span.slot] = true
+	case evRetry:
+		tg.instant = append(tg.instant, traceEvent{
+			Name: fmt.Sprintf("retry #%d", e.attempt), Cat: "retry", Ph: "i", Ts: t.ts(e.at), Tid: span.slot + 1, S: "t",
+			Args: map[string]any{"node": nodeName(e.n), "error": e.err.Error()},
+		})
+	case evTimeout:
+		tg.instant = append(tg.instant, traceEvent{
+			Name: "timeout", Cat: "timeout", Ph: "i", Ts: t.ts(e.at), Tid: span.slot + 1, S: "t",
+			Args: map[string]any{"node": nodeName(e.n), "timeout": e.n.timeout.String()},
+		})
+	case evCacheHit, evCacheMiss:
+		name := map[eventKind]string{evCacheHit: "cache hit", evCacheMiss: "cache miss"}[e.kind]
+		tg.instant = append(tg.instant, traceEvent{
+			Name: name, Cat: "cache", Ph: "i", Ts: t.ts(e.at), Tid: span.slot + 1, S: "t",
+			Args: map[string]any{"node": nodeName(e.n)},
+		})
+	case evEnd:
+		span.end, span.err = e.at, e.err
+		if span.slot >= 0 {
+			tg.slots[span.slot] = false
+		}
+	}
+}
+
+func (t *Trace) group(g *Group) *traceGroup {
+	for _, tg := range t.groups {
+		if tg.g == g {
+			return tg
+		}
+	}
+	tg := &traceGroup{g: g, spans: make(map[*node]*traceSpan)}
+	t.groups = append(t.groups, tg)
+	return tg
+}
+
+// ts converts time to trace timestamp in microseconds
+func (t *Trace) ts(at time.Time) float64 {
+	return float64(at.Sub(t.start).Nanoseconds()) / 1e3
+}
+
+// WriteTo writes the trace as Chrome Trace Event JSON
+func (t *Trace) WriteTo(w io.Writer) (int64, error) {
+	t.mu.Lock()
+	events := t.events()
+	t.mu.Unlock()
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(struct {
+		TraceEvents     []traceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{events, "ms"}); err != nil {
+		return 0, fmt.Errorf("failed to encode trace: %w", err)
+	}
+	return buf.WriteTo(w)
+}
+
+func (t *Trace) events() []traceEvent {
+	events := make([]traceEvent, 0)
+	var id int
+	for i, tg := range t.groups {
+		pid := i + 1
+		events = append(events, traceEvent{Name: "process_name", Ph: "M", Pid: pid, Args: map[string]any{"name": "group " + tg.g.prefix}})
+		events = append(events, traceEvent{Name: "thread_name", Ph: "M", Pid: pid, Tid: 0, Args: map[string]any{"name": "limit wait"}})
+		for slot := range tg.slots {
+			events = append(events, traceEvent{Name: "thread_name", Ph: "M", Pid: pid, Tid: slot + 1, Args: map[string]any{"name": fmt.Sprintf("slot %d", slot)}})
+		}
+
+		spans := make([]*traceSpan, 0, len(tg.spans))
+		for _, span := range tg.spans {
+			if !span.start.IsZero() {
+				spans = append(spans, span)
+			}
+		}
+		slices.SortFunc(spans, func(a, b *traceSpan) int { return a.start.Compare(b.start) })
+		for _, span := range spans {
+			name := nodeName(span.n)
+			end := span.end
+			if end.IsZero() { // unfinished node
+				end = span.start
+			}
+			args := map[string]any{"key": name}
+			if span.err != nil {
+				args["error"] = span.err.Error()
+			}
+			events = append(events, traceEvent{
+				Name: name, Cat: "node", Ph: "X", Ts: t.ts(span.start), Dur: t.ts(end) - t.ts(span.start), Pid: pid, Tid: span.slot + 1, Args: args,
+			})
+			// limit wait
+			if !span.ready.IsZero() && span.start.After(span.ready) {
+				id++
+				events = append(events,
+					traceEvent{Name: "wait " + name, Cat: "wait", Ph: "b", Ts: t.ts(span.ready), Pid: pid, ID: id},
+					traceEvent{Name: "wait " + name, Cat: "wait", Ph: "e", Ts: t.ts(span.start), Pid: pid, ID: id},
+				)
+			}
+			// dependency flows
+			for _, dep := range span.deps {
+				from := tg.spans[dep]
+				if from == nil || from.end.IsZero() {
+					continue
+				}
+				id++
+				events = append(events,
+					traceEvent{Name: "dependency", Cat: "dependency", Ph: "s", Ts: max(t.ts(from.end)-1, t.ts(from.start)), Pid: pid, Tid: from.slot + 1, ID: id},
+					traceEvent{Name: "dependency", Cat: "dependency", Ph: "f", Ts: t.ts(span.start), Pid: pid, Tid: span.slot + 1, ID: id, Bp: "e"},
+				)
+			}
+		}
+		for _, e := range tg.instant {
+			e.Pid = pid
+			events = append(events, e)
+		}
+	}
+	return events
+}
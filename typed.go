@@ -0,0 +1,449 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// TypedGroup is a group whose shared state is of type S
+/*
+ * shared funcs, conditions, interceptors and rollback funcs receive S directly,
+ * nodes are scheduled by the underlying Group with the same options
+ * untyped adders (AddNode / AddAutoNode) and WithFunc are not exposed
+ */
+type TypedGroup[S any] struct {
+	g *Group
+}
+
+// TypedNode is a node of a TypedGroup
+type TypedNode[S any] struct {
+	n     *node
+	Group *TypedGroup[S]
+}
+
+// TypedNodes is a batch of nodes of a TypedGroup
+type TypedNodes[S any] struct {
+	ns    *nodes
+	Group *TypedGroup[S]
+}
+
+func NewGroupOf[S any](opts ...option) *TypedGroup[S] {
+	return &TypedGroup[S]{NewGroup(opts...)}
+}
+
+// Go executes the group with shared state s
+func (g *TypedGroup[S]) Go(ctx context.Context, s S) error {
+	return g.g.Go(ctx, s)
+}
+
+// Profile executes the group with shared state s as Group.Profile does
+func (g *TypedGroup[S]) Profile(ctx context.Context, s S) (*RunProfile, error) {
+	return g.g.Profile(ctx, s)
+}
+
+// sharedOf returns the typed shared state, zero value if absent
+/*
+ * panics on a shared state of another type (recovered as a node failure by the group)
+ */
+func sharedOf[S any](v any) S {
+	if v == nil {
+		var zero S
+		return zero
+	}
+	s, ok := v.(S)
+	if !ok {
+		panic(fmt.Sprintf("shared state of type %T is not %T", v, s))
+	}
+	return s
+}
+
+func sharedRunner[S any](runner func(S) error) func(any) error {
+	return func(v any) error { return runner(sharedOf[S](v)) }
+}
+
+func autoSharedRunner[S any](runner func(S) (any, error)) func(any) (any, error) {
+	return func(v any) (any, error) { return runner(sharedOf[S](v)) }
+}
+
+func sharedTask[S any](task func(context.Context, S) error) func(context.Context, any) error {
+	return func(ctx context.Context, v any) error { return task(ctx, sharedOf[S](v)) }
+}
+
+func autoSharedTask[S any](task func(context.Context, S) (any, error)) func(context.Context, any) (any, error) {
+	return func(ctx context.Context, v any) (any, error) { return task(ctx, sharedOf[S](v)) }
+}
+
+// untyped converts typed funcs to untyped ones
+func untyped[F, G any](fs []F, f func(F) G) []G {
+	gs := make([]G, len(fs))
+	for i, fn := range fs {
+		gs[i] = f(fn)
+	}
+	return gs
+}
+
+func (g *TypedGroup[S]) typed(n *node) *TypedNode[S] {
+	return &TypedNode[S]{n: n, Group: g}
+}
+
+func (g *TypedGroup[S]) typedNodes(ns *nodes) *TypedNodes[S] {
+	return &TypedNodes[S]{ns: ns, Group: g}
+}
+
+// region Adding Operations
+
+func (g *TypedGroup[S]) AddRunner(runner func() error) *TypedNode[S] {
+	return g.typed(g.g.AddRunner(runner))
+}
+
+func (g *TypedGroup[S]) AddSharedRunner(runner func(S) error) *TypedNode[S] {
+	return g.typed(g.g.AddSharedRunner(sharedRunner(runner)))
+}
+
+func (g *TypedGroup[S]) AddAutoRunner(runner func() (any, error)) *TypedNode[S] {
+	return g.typed(g.g.AddAutoRunner(runner))
+}
+
+func (g *TypedGroup[S]) AddAutoSharedRunner(runner func(S) (any, error)) *TypedNode[S] {
+	return g.typed(g.g.AddAutoSharedRunner(autoSharedRunner(runner)))
+}
+
+func (g *TypedGroup[S]) AddTask(task func(context.Context) error) *TypedNode[S] {
+	return g.typed(g.g.AddTask(task))
+}
+
+func (g *TypedGroup[S]) AddSharedTask(task func(context.Context, S) error) *TypedNode[S] {
+	return g.typed(g.g.AddSharedTask(sharedTask(task)))
+}
+
+func (g *TypedGroup[S]) AddAutoTask(task func(context.Context) (any, error)) *TypedNode[S] {
+	return g.typed(g.g.AddAutoTask(task))
+}
+
+func (g *TypedGroup[S]) AddAutoSharedTask(task func(context.Context, S) (any, error)) *TypedNode[S] {
+	return g.typed(g.g.AddAutoSharedTask(autoSharedTask(task)))
+}
+
+// region Batch Adding Operations
+
+func (g *TypedGroup[S]) AddRunners(runners ...func() error) *TypedNodes[S] {
+	return g.typedNodes(g.g.AddRunners(runners...))
+}
+
+func (g *TypedGroup[S]) AddSharedRunners(runners ...func(S) error) *TypedNodes[S] {
+	return g.typedNodes(g.g.AddSharedRunners(untyped(runners, sharedRunner[S])...))
+}
+
+func (g *TypedGroup[S]) AddAutoRunners(runners ...func() (any, error)) *TypedNodes[S] {
+	return g.typedNodes(g.g.AddAutoRunners(runners...))
+}
+
+func (g *TypedGroup[S]) AddAutoSharedRunners(runners ...func(S) (any, error)) *TypedNodes[S] {
+	return g.typedNodes(g.g.AddAutoSharedRunners(untyped(runners, autoSharedRunner[S])...))
+}
+
+func (g *TypedGroup[S]) AddTasks(tasks ...func(context.Context) error) *TypedNodes[S] {
+	return g.typedNodes(g.g.AddTasks(tasks...))
+}
+
+func (g *TypedGroup[S]) AddSharedTasks(tasks ...func(context.Context, S) error) *TypedNodes[S] {
+	return g.typedNodes(g.g.AddSharedTasks(untyped(tasks, sharedTask[S])...))
+}
+
+func (g *TypedGroup[S]) AddAutoTasks(tasks ...func(context.Context) (any, error)) *TypedNodes[S] {
+	return g.typedNodes(g.g.AddAutoTasks(tasks...))
+}
+
+func (g *TypedGroup[S]) AddAutoSharedTasks(tasks ...func(context.Context, S) (any, error)) *TypedNodes[S] {
+	return g.typedNodes(g.g.AddAutoSharedTasks(untyped(tasks, autoSharedTask[S])...))
+}
+
+// region Serial Adding Operations
+
+func (g *TypedGroup[S]) AddSerialRunners(runners ...func() error) *TypedNode[S] {
+	return g.typed(g.g.AddSerialRunners(runners...))
+}
+
+func (g *TypedGroup[S]) AddSerialSharedRunners(runners ...func(S) error) *TypedNode[S] {
+	return g.typed(g.g.AddSerialSharedRunners(untyped(runners, sharedRunner[S])...))
+}
+
+func (g *TypedGroup[S]) AddSerialAutoRunners(runners ...func() (any, error)) *TypedNode[S] {
+	return g.typed(g.g.AddSerialAutoRunners(runners...))
+}
+
+func (g *TypedGroup[S]) AddSerialAutoSharedRunners(runners ...func(S) (any, error)) *TypedNode[S] {
+	return g.typed(g.g.AddSerialAutoSharedRunners(untyped(runners, autoSharedRunner[S])...))
+}
+
+func (g *TypedGroup[S]) AddSerialTasks(tasks ...func(context.Context) error) *TypedNode[S] {
+	return g.typed(g.g.AddSerialTasks(tasks...))
+}
+
+func (g *TypedGroup[S]) AddSerialSharedTasks(tasks ...func(context.Context, S) error) *TypedNode[S] {
+	return g.typed(g.g.AddSerialSharedTasks(untyped(tasks, sharedTask[S])...))
+}
+
+func (g *TypedGroup[S]) AddSerialAutoTasks(tasks ...func(context.Context) (any, error)) *TypedNode[S] {
+	return g.typed(g.g.AddSerialAutoTasks(tasks...))
+}
+
+func (g *TypedGroup[S]) AddSerialAutoSharedTasks(tasks ...func(context.Context, S) (any, error)) *TypedNode[S] {
+	return g.typed(g.g.AddSerialAutoSharedTasks(untyped(tasks, autoSharedTask[S])...))
+}
+
+// region Common Operations
+
+// Get node by key
+func (g *TypedGroup[S]) Node(key any) *TypedNode[S] {
+	if n := g.g.Node(key); n != nil {
+		return g.typed(n)
+	}
+	return nil
+}
+
+func (g *TypedGroup[S]) Err() error {
+	return g.g.Err()
+}
+
+func (g *TypedGroup[S]) Build() (*TypedGroup[S], error) {
+	_, err := g.g.Build()
+	return g, err
+}
+
+func (g *TypedGroup[S]) Validate() error {
+	return g.g.Validate()
+}
+
+func (g *TypedGroup[S]) Issues() []Issue {
+	return g.g.Issues()
+}
+
+func (g *TypedGroup[S]) Verify(panicking bool) string {
+	return g.g.Verify(panicking)
+}
+
+func (g *TypedGroup[S]) Levels() ([][]any, error) {
+	return g.g.Levels()
+}
+
+func (g *TypedGroup[S]) CriticalPath() ([]any, error) {
+	return g.g.CriticalPath()
+}
+
+func (g *TypedGroup[S]) Clone() *TypedGroup[S] {
+	return &TypedGroup[S]{g.g.Clone()}
+}
+
+func (g *TypedGroup[S]) WithOptions(opts ...option) *TypedGroup[S] {
+	g.g.WithOptions(opts...)
+	return g
+}
+
+func (g *TypedGroup[S]) Remove(key any, policy ...RemovePolicy) *TypedGroup[S] {
+	g.g.Remove(key, policy...)
+	return g
+}
+
+// Include adds the nodes of other as Group.Include does
+func (g *TypedGroup[S]) Include(other *TypedGroup[S], namespace string, policy ...ConflictPolicy) *TypedGroup[S] {
+	g.g.Include(other.g, namespace, policy...)
+	return g
+}
+
+func (g *TypedGroup[S]) Mermaid(opts *GraphOptions) string {
+	return g.g.Mermaid(opts)
+}
+
+func (g *TypedGroup[S]) DOT(ctx context.Context, opts *GraphOptions) (string, error) {
+	return g.g.DOT(ctx, opts)
+}
+
+func (g *TypedGroup[S]) RenderGraph(ctx context.Context, opts *GraphOptions, w io.Writer) error {
+	return g.g.RenderGraph(ctx, opts, w)
+}
+
+func (g *TypedGroup[S]) RenderGraphToFile(ctx context.Context, opts *GraphOptions, filename string) error {
+	return g.g.RenderGraphToFile(ctx, opts, filename)
+}
+
+// region Chaining Operations
+
+func (n *TypedNode[S]) AddRunner(runner func() error) *TypedNode[S] {
+	return n.Group.AddRunner(runner)
+}
+
+func (n *TypedNode[S]) AddSharedRunner(runner func(S) error) *TypedNode[S] {
+	return n.Group.AddSharedRunner(runner)
+}
+
+func (n *TypedNode[S]) AddAutoRunner(runner func() (any, error)) *TypedNode[S] {
+	return n.Group.AddAutoRunner(runner)
+}
+
+func (n *TypedNode[S]) AddAutoSharedRunner(runner func(S) (any, error)) *TypedNode[S] {
+	return n.Group.AddAutoSharedRunner(runner)
+}
+
+func (n *TypedNode[S]) AddTask(task func(context.Context) error) *TypedNode[S] {
+	return n.Group.AddTask(task)
+}
+
+func (n *TypedNode[S]) AddSharedTask(task func(context.Context, S) error) *TypedNode[S] {
+	return n.Group.AddSharedTask(task)
+}
+
+func (n *TypedNode[S]) AddAutoTask(task func(context.Context) (any, error)) *TypedNode[S] {
+	return n.Group.AddAutoTask(task)
+}
+
+func (n *TypedNode[S]) AddAutoSharedTask(task func(context.Context, S) (any, error)) *TypedNode[S] {
+	return n.Group.AddAutoSharedTask(task)
+}
+
+func (n *TypedNode[S]) Key(key any) *TypedNode[S] {
+	n.n.Key(key)
+	return n
+}
+
+func (n *TypedNode[S]) Dep(keys ...any) *TypedNode[S] {
+	n.n.Dep(keys...)
+	return n
+}
+
+func (n *TypedNode[S]) WeakDep(keys ...any) *TypedNode[S] {
+	n.n.WeakDep(keys...)
+	return n
+}
+
+func (n *TypedNode[S]) FastFail() *TypedNode[S] {
+	n.n.FastFail()
+	return n
+}
+
+func (n *TypedNode[S]) SilentFail() *TypedNode[S] {
+	n.n.SilentFail()
+	return n
+}
+
+func (n *TypedNode[S]) WithRetry(times int) *TypedNode[S] {
+	n.n.WithRetry(times)
+	return n
+}
+
+func (n *TypedNode[S]) WithTimeout(t time.Duration) *TypedNode[S] {
+	n.n.WithTimeout(t)
+	return n
+}
+
+func (n *TypedNode[S]) SkipIf(skip bool) *TypedNode[S] {
+	n.n.SkipIf(skip)
+	return n
+}
+
+func (n *TypedNode[S]) WithPreFunc(f func(ctx context.Context, shared S) error) *TypedNode[S] {
+	n.n.WithPreFunc(sharedTask(f))
+	return n
+}
+
+func (n *TypedNode[S]) WithAfterFunc(f func(ctx context.Context, shared S, err error) error) *TypedNode[S] {
+	n.n.WithAfterFunc(func(ctx context.Context, v any, err error) error { return f(ctx, sharedOf[S](v), err) })
+	return n
+}
+
+func (n *TypedNode[S]) WithRollback(f func(ctx context.Context, shared S, err error) error) *TypedNode[S] {
+	n.n.WithRollback(func(ctx context.Context, v any, err error) error { return f(ctx, sharedOf[S](v), err) })
+	return n
+}
+
+func (n *TypedNode[S]) WithCondition(f func(ctx context.Context, shared S) bool) *TypedNode[S] {
+	n.n.WithCondition(func(ctx context.Context, v any) bool { return f(ctx, sharedOf[S](v)) })
+	return n
+}
+
+func (n *TypedNode[S]) WithCache(cache Cache, keyFunc func(ctx context.Context, shared S) string, ttl time.Duration) *TypedNode[S] {
+	var f CacheKeyFunc
+	if keyFunc != nil {
+		f = func(ctx context.Context, v any) string { return keyFunc(ctx, sharedOf[S](v)) }
+	}
+	n.n.WithCache(cache, f, ttl)
+	return n
+}
+
+func (n *TypedNode[S]) Dedup(keyFunc func(ctx context.Context, shared S) string) *TypedNode[S] {
+	var f DedupKeyFunc
+	if keyFunc != nil {
+		f = func(ctx context.Context, v any) string { return keyFunc(ctx, sharedOf[S](v)) }
+	}
+	n.n.Dedup(f)
+	return n
+}
+
+func (n *TypedNode[S]) Verify(panicking bool) *TypedNode[S] {
+	n.n.Verify(panicking)
+	return n
+}
+
+// region Batch Chaining Operations
+
+func (ns *TypedNodes[S]) Keys(keys ...any) *TypedNodes[S] {
+	ns.ns.Keys(keys...)
+	return ns
+}
+
+func (ns *TypedNodes[S]) Dep(keys ...any) *TypedNodes[S] {
+	ns.ns.Dep(keys...)
+	return ns
+}
+
+func (ns *TypedNodes[S]) WeakDep(keys ...any) *TypedNodes[S] {
+	ns.ns.WeakDep(keys...)
+	return ns
+}
+
+func (ns *TypedNodes[S]) FastFail() *TypedNodes[S] {
+	ns.ns.FastFail()
+	return ns
+}
+
+func (ns *TypedNodes[S]) SilentFail() *TypedNodes[S] {
+	ns.ns.SilentFail()
+	return ns
+}
+
+func (ns *TypedNodes[S]) WithRetry(times int) *TypedNodes[S] {
+	ns.ns.WithRetry(times)
+	return ns
+}
+
+func (ns *TypedNodes[S]) WithTimeout(t time.Duration) *TypedNodes[S] {
+	ns.ns.WithTimeout(t)
+	return ns
+}
+
+func (ns *TypedNodes[S]) SkipIf(skip bool) *TypedNodes[S] {
+	ns.ns.SkipIf(skip)
+	return ns
+}
+
+func (ns *TypedNodes[S]) WithPreFunc(f func(ctx context.Context, shared S) error) *TypedNodes[S] {
+	ns.ns.WithPreFunc(sharedTask(f))
+	return ns
+}
+
+func (ns *TypedNodes[S]) WithAfterFunc(f func(ctx context.Context, shared S, err error) error) *TypedNodes[S] {
+	ns.ns.WithAfterFunc(func(ctx context.Context, v any, err error) error { return f(ctx, sharedOf[S](v), err) })
+	return ns
+}
+
+func (ns *TypedNodes[S]) WithRollback(f func(ctx context.Context, shared S, err error) error) *TypedNodes[S] {
+	ns.ns.WithRollback(func(ctx context.Context, v any, err error) error { return f(ctx, sharedOf[S](v), err) })
+	return ns
+}
+
+func (ns *TypedNodes[S]) WithCondition(f func(ctx context.Context, shared S) bool) *TypedNodes[S] {
+	ns.ns.WithCondition(func(ctx context.Context, v any) bool { return f(ctx, sharedOf[S](v)) })
+	return ns
+}
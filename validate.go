@@ -0,0 +1,481 @@
+package group
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// IssueKind is the kind of a graph validation issue
+type IssueKind uint8
+
+const (
+	IssueCycle             IssueKind = iota // dependency cycle
+	IssueUnreachable                        // node never runs since it is downstream of a dependency cycle
+	IssueSilentBlock                        // node is blocked without error if its silent-fail upstream fails
+	IssueFastFailWeak                       // weak dependency on a fast-fail node only takes effect on success (group halts on failure)
+	IssueWeakChain                          // chain of nodes connected by weak dependencies only
+	IssueRedundantEdge                      // dependency already implied by another dependency path
+	IssueOptionConflict                     // conflicting node and group options
+	IssueMissingDependency                  // dependency on a key never added
+)
+
+func (k IssueKind) String() string {
+	switch k {
+	case IssueCycle:
+		return "cycle"
+	case IssueUnreachable:
+		return "unreachable"
+	case IssueSilentBlock:
+		return "silent block"
+	case IssueFastFailWeak:
+		return "fast-fail weak dependency"
+	case IssueWeakChain:
+		return "weak chain"
+	case IssueRedundantEdge:
+		return "redundant edge"
+	case IssueOptionConflict:
+		return "option conflict"
+	case IssueMissingDependency:
+		return "missing dependency"
+	default:
+		return "unknown"
+	}
+}
+
+// Severity is the severity of a graph validation issue
+type Severity uint8
+
+const (
+	SeverityError   Severity = iota // the group does not run as declared
+	SeverityWarning                 // the setup may be deliberate
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Severity returns the severity of issues of kind k
+/*
+ * silent blocks, weak dependencies on fast-fail nodes, weak chains and redundant edges are warnings,
+ * other issues are errors
+ */
+func (k IssueKind) Severity() Severity {
+	switch k {
+	case IssueSilentBlock, IssueFastFailWeak, IssueWeakChain, IssueRedundantEdge:
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
+
+// Issue is a graph validation issue with the offending node keys
+/*
+ * anonymous nodes are reported by name (node_<idx>)
+ */
+type Issue struct {
+	Kind IssueKind
+	Keys []any
+	Msg  string
+}
+
+func (i Issue) Severity() Severity {
+	return i.Kind.Severity()
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s", i.Kind, i.Msg)
+}
+
+// ValidationError lists all issues found by Validate
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "group validation failed with %d issue(s)", len(e.Issues))
+	for _, issue := range e.Issues {
+		b.WriteString("\n- ")
+		if issue.Severity() == SeverityWarning {
+			b.WriteString("warning: ")
+		}
+		b.WriteString(issue.String())
+	}
+	return b.String()
+}
+
+// Is reports ErrCycle / ErrMissingDependency if any cycle / missing dependency is found
+func (e *ValidationError) Is(target error) bool {
+	switch target {
+	case ErrCycle:
+		return len(e.Filter(IssueCycle)) > 0
+	case ErrMissingDependency:
+		return len(e.Filter(IssueMissingDependency)) > 0
+	}
+	return false
+}
+
+// Filter returns issues of the given kinds
+func (e *ValidationError) Filter(kinds ...IssueKind) []Issue {
+	var issues []Issue
+	for _, issue := range e.Issues {
+		if slices.Contains(kinds, issue.Kind) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Validate checks the dependency graph and options, returns *ValidationError listing all issues (warnings included)
+// if any issue is an error, see Issues
+func (g *Group) Validate() error {
+	issues := g.Issues()
+	if !slices.ContainsFunc(issues, func(i Issue) bool { return i.Severity() == SeverityError }) {
+		return nil
+	}
+	return &ValidationError{Issues: issues}
+}
+
+// Issues returns all issues of the dependency graph and options
+/*
+ * errors:
+ * - every dependency cycle and nodes unreachable downstream of cycles
+ * - weak dependencies on fast-fail nodes
+ * - option conflicts (e.g. node timeout not less than group timeout)
+ * - dependencies on keys never added
+ * warnings:
+ * - nodes silently blocked by silent-fail upstreams
+ * - chains of weak-only dependencies
+ * - redundant (transitively implied) strong dependencies
+ */
+func (g *Group) Issues() []Issue {
+	var issues []Issue
+	issues = append(issues, g.missingDependencyIssues()...)
+	issues = append(issues, g.cycleIssues()...)
+	issues = append(issues, g.failIssues()...)
+	issues = append(issues, g.weakChainIssues()...)
+	issues = append(issues, g.redundantEdgeIssues()...)
+	issues = append(issues, g.optionIssues()...)
+	return issues
+}
+
+// issueKey is the key of n reported in issues
+func issueKey(n *node) any {
+	if n.key != nil {
+		return n.key
+	}
+	return nodeName(n)
+}
+
+func issueKeys(ns ...*node) []any {
+	keys := make([]any, 0, len(ns))
+	for _, n := range ns {
+		keys = append(keys, issueKey(n))
+	}
+	return keys
+}
+
+func issuePath(ns []*node) string {
+	names := make([]string, 0, len(ns))
+	for _, n := range ns {
+		names = append(names, fmt.Sprintf("%q", nodeName(n)))
+	}
+	return strings.Join(names, " -> ")
+}
+
+// missingDependencyIssues resolves pending dependencies and reports the unresolved ones
+func (g *Group) missingDependencyIssues() []Issue {
+	if g.resolve() == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var issues []Issue
+	for _, p := range g.pending {
+		n := g.nodes[p.idx]
+		issues = append(issues, Issue{
+			Kind: IssueMissingDependency,
+			Keys: []any{issueKey(n), p.key},
+			Msg:  fmt.Sprintf("%q -> %q", nodeName(n), fmt.Sprint(p.key)),
+		})
+	}
+	return issues
+}
+
+// cycleIssues reports a cycle for every strongly connected component and the nodes downstream of them
+func (g *Group) cycleIssues() []Issue {
+	var issues []Issue
+	inCycle := make([]bool, len(g.nodes))
+	for _, scc := range g.sccs() {
+		if len(scc) == 1 && !slices.Contains(g.nodes[scc[0]].deps, scc[0]) {
+			continue
+		}
+		cycle := g.cycleIn(scc)
+		for _, n := range cycle {
+			inCycle[n.idx] = true
+		}
+		issues = append(issues, Issue{
+			Kind: IssueCycle,
+			Keys: issueKeys(cycle...),
+			Msg:  issuePath(append(cycle, cycle[0])),
+		})
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	// nodes that never become ready
+	ready := make([]bool, len(g.nodes))
+	indegree := make([]int, len(g.nodes))
+	queue := make([]int, 0, len(g.nodes))
+	for i, n := range g.nodes {
+		if indegree[i] = len(n.deps); indegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for i := 0; i < len(queue); i++ {
+		ready[queue[i]] = true
+		for _, toIdx := range g.nodes[queue[i]].to {
+			if indegree[toIdx]--; indegree[toIdx] == 0 {
+				queue = append(queue, toIdx)
+			}
+		}
+	}
+	for i, n := range g.nodes {
+		if !ready[i] && !inCycle[i] {
+			issues = append(issues, Issue{
+				Kind: IssueUnreachable,
+				Keys: issueKeys(n),
+				Msg:  fmt.Sprintf("%q is downstream of a dependency cycle", nodeName(n)),
+			})
+		}
+	}
+	return issues
+}
+
+// sccs returns strongly connected components of the dependency graph (tarjan)
+func (g *Group) sccs() [][]int {
+	var (
+		index   = make([]int, len(g.nodes))
+		low     = make([]int, len(g.nodes))
+		onStack = make([]bool, len(g.nodes))
+		stack   []int
+		sccs    [][]int
+		counter = 1
+	)
+	var strongConnect func(v int)
+	strongConnect = func(v int) {
+		index[v], low[v] = counter, counter
+		counter++
+		stack, onStack[v] = append(stack, v), true
+		for _, w := range g.nodes[v].deps {
+			if index[w] == 0 {
+				strongConnect(w)
+				low[v] = min(low[v], low[w])
+			} else if onStack[w] {
+				low[v] = min(low[v], index[w])
+			}
+		}
+		if low[v] == index[v] {
+			var scc []int
+			for {
+				w := stack[len(stack)-1]
+				stack, onStack[w] = stack[:len(stack)-1], false
+				scc = append(scc, w)
+				if w == v {
+					break
+				}
+			}
+			sccs = append(sccs, scc)
+		}
+	}
+	for v := range g.nodes {
+		if index[v] == 0 {
+			strongConnect(v)
+		}
+	}
+	return sccs
+}
+
+// cycleIn returns a cycle through the first node of a strongly connected component (in dependency order)
+func (g *Group) cycleIn(scc []int) []*node {
+	start := slices.Min(scc)
+	in := make(map[int]bool, len(scc))
+	for _, idx := range scc {
+		in[idx] = true
+	}
+	// bfs back to start through deps within the component
+	prev := map[int]int{start: -1}
+	queue := []int{start}
+	for i := 0; i < len(queue); i++ {
+		for _, dep := range g.nodes[queue[i]].deps {
+			if !in[dep] {
+				continue
+			}
+			if dep == start {
+				// found: start -> ... -> queue[i] -> start (following deps)
+				var path []*node
+				for cur := queue[i]; cur != -1; cur = prev[cur] {
+					path = append(path, g.nodes[cur])
+				}
+				slices.Reverse(path)
+				return path
+			}
+			if _, ok := prev[dep]; !ok {
+				prev[dep] = queue[i]
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return []*node{g.nodes[start]}
+}
+
+// failIssues reports nodes affected by silent-fail / fast-fail upstreams
+func (g *Group) failIssues() []Issue {
+	var issues []Issue
+	for _, n := range g.nodes {
+		for _, depIdx := range n.deps {
+			dep := g.nodes[depIdx]
+			weak := slices.Contains(dep.weakTo, n.idx)
+			switch {
+			case dep.sf && !dep.ff && !weak:
+				issues = append(issues, Issue{
+					Kind: IssueSilentBlock,
+					Keys: issueKeys(dep, n),
+					Msg:  fmt.Sprintf("%q is blocked without error if silent-fail %q fails", nodeName(n), nodeName(dep)),
+				})
+			case dep.ff && weak:
+				issues = append(issues, Issue{
+					Kind: IssueFastFailWeak,
+					Keys: issueKeys(dep, n),
+					Msg:  fmt.Sprintf("weak dependency %q -> %q never takes effect since fast-fail %q halts the group", nodeName(n), nodeName(dep), nodeName(dep)),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// weakChainIssues reports the longest chain of at least 3 nodes connected by weak dependencies only
+// ending at each chain tail
+func (g *Group) weakChainIssues() []Issue {
+	if _, err := g.levels(); err != nil {
+		return nil // chains are undefined with cycles
+	}
+	weakOnly := func(n *node) bool {
+		if len(n.deps) == 0 {
+			return false
+		}
+		for _, depIdx := range n.deps {
+			if !slices.Contains(g.nodes[depIdx].weakTo, n.idx) {
+				return false
+			}
+		}
+		return true
+	}
+	// memoized longest chain ending at each node
+	length, prev := make([]int, len(g.nodes)), make([]int, len(g.nodes))
+	var longest func(idx int) int
+	longest = func(idx int) int {
+		if length[idx] > 0 {
+			return length[idx]
+		}
+		n := g.nodes[idx]
+		length[idx], prev[idx] = 1, -1
+		if weakOnly(n) { // chain heads are nodes not weakly chained from upstreams
+			for _, depIdx := range n.deps {
+				if l := longest(depIdx) + 1; l > length[idx] {
+					length[idx], prev[idx] = l, depIdx
+				}
+			}
+		}
+		return length[idx]
+	}
+	var issues []Issue
+	for _, n := range g.nodes {
+		if !weakOnly(n) || slices.ContainsFunc(n.weakTo, func(toIdx int) bool { return weakOnly(g.nodes[toIdx]) }) {
+			continue // not a chain tail
+		}
+		if longest(n.idx) < 3 {
+			continue
+		}
+		var path []*node
+		for idx := n.idx; idx != -1; idx = prev[idx] {
+			path = append(path, g.nodes[idx])
+		}
+		slices.Reverse(path)
+		issues = append(issues, Issue{
+			Kind: IssueWeakChain,
+			Keys: issueKeys(path...),
+			Msg:  issuePath(path),
+		})
+	}
+	return issues
+}
+
+// redundantEdgeIssues reports strong dependencies implied by another strong dependency path
+func (g *Group) redundantEdgeIssues() []Issue {
+	if _, err := g.levels(); err != nil {
+		return nil // reachability is undefined with cycles
+	}
+	// memoized strong reachability
+	reach := make([][]bool, len(g.nodes))
+	var reachable func(from int) []bool
+	reachable = func(from int) []bool {
+		if reach[from] != nil {
+			return reach[from]
+		}
+		r := make([]bool, len(g.nodes))
+		for _, toIdx := range g.nodes[from].to {
+			if slices.Contains(g.nodes[from].weakTo, toIdx) {
+				continue
+			}
+			r[toIdx] = true
+			for i, ok := range reachable(toIdx) {
+				r[i] = r[i] || ok
+			}
+		}
+		reach[from] = r
+		return r
+	}
+	var issues []Issue
+	for _, n := range g.nodes {
+		for _, depIdx := range n.deps {
+			dep := g.nodes[depIdx]
+			if slices.Contains(dep.weakTo, n.idx) {
+				continue
+			}
+			for _, viaIdx := range n.deps {
+				via := g.nodes[viaIdx]
+				if viaIdx == depIdx || slices.Contains(via.weakTo, n.idx) || !reachable(depIdx)[viaIdx] {
+					continue
+				}
+				issues = append(issues, Issue{
+					Kind: IssueRedundantEdge,
+					Keys: issueKeys(dep, n),
+					Msg:  fmt.Sprintf("%q -> %q is implied by %q", nodeName(n), nodeName(dep), nodeName(via)),
+				})
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// optionIssues reports conflicting node and group options
+func (g *Group) optionIssues() []Issue {
+	var issues []Issue
+	for _, n := range g.nodes {
+		if g.timeout > 0 && n.timeout >= g.timeout {
+			issues = append(issues, Issue{
+				Kind: IssueOptionConflict,
+				Keys: issueKeys(n),
+				Msg:  fmt.Sprintf("%q timeout %s is not less than group timeout %s", nodeName(n), n.timeout, g.timeout),
+			})
+		}
+	}
+	return issues
+}